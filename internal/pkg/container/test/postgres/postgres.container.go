@@ -29,8 +29,32 @@ type Options struct {
 	Timeout   time.Duration
 }
 
-// Start initializes a PostgreSQL container and returns a gorm DB instance, sqlmock, and any error occurred.
-func Start(ctx context.Context, t *testing.T) (*gorm.DB, sqlmock.Sqlmock, error) {
+// Start initializes a seeded PostgreSQL container and returns a gorm DB instance connected to it.
+// Use NewMock for a DB driven by sqlmock expectations instead.
+func Start(ctx context.Context, t *testing.T) (*gorm.DB, error) {
+	DB, _, err := StartWithConfig(ctx, t)
+	return DB, err
+}
+
+// NewMock returns a gorm DB instance backed by sqlmock, so repository calls can be driven
+// by expectations without starting a container.
+func NewMock() (*gorm.DB, sqlmock.Sqlmock, error) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to create sqlmock")
+	}
+
+	DB, err := ormpgsql.NewORMWithDB(sqlDB, nil)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to open gorm on sqlmock")
+	}
+
+	return DB, mock, nil
+}
+
+// StartWithConfig initializes a seeded PostgreSQL container and returns a gorm DB instance
+// together with the configuration used to connect to it.
+func StartWithConfig(ctx context.Context, t *testing.T) (*gorm.DB, *ormpgsql.PostgresConfig, error) {
 	options := getDefaultPostgresOptions()
 	containerReq := getContainerRequest(options)
 
@@ -45,21 +69,16 @@ func Start(ctx context.Context, t *testing.T) (*gorm.DB, sqlmock.Sqlmock, error)
 		}
 	})
 
-	DB, err := createORMConnection(ctx, postgresContainer, options)
+	DB, config, err := createORMConnection(ctx, postgresContainer, options)
 	if err != nil {
 		return nil, nil, errors.Wrap(err, "failed to create ORM connection")
 	}
 
-	mock, err := setupSQLMock()
-	if err != nil {
-		return nil, nil, errors.Wrap(err, "failed to create mock object")
-	}
-
 	if err := loadSeed(DB); err != nil {
 		return nil, nil, err
 	}
 
-	return DB, mock, nil
+	return DB, config, nil
 }
 
 // getDefaultPostgresOptions returns the default configuration for PostgreSQL container.
@@ -110,8 +129,9 @@ func stopContainer(ctx context.Context, container testcontainers.Container) erro
 	return errors.Wrap(container.Terminate(ctx), "failed to terminate container")
 }
 
-// createORMConnection establishes a GORM connection using provided PostgreSQL container and options.
-func createORMConnection(ctx context.Context, container testcontainers.Container, opts *Options) (*gorm.DB, error) {
+// createORMConnection establishes a GORM connection using provided PostgreSQL container and options
+// and returns it together with the resolved connection configuration.
+func createORMConnection(ctx context.Context, container testcontainers.Container, opts *Options) (*gorm.DB, *ormpgsql.PostgresConfig, error) {
 	bo := backoff.NewExponentialBackOff()
 	bo.MaxElapsedTime = 10 * time.Second
 	const maxRetries = 5
@@ -147,19 +167,10 @@ func createORMConnection(ctx context.Context, container testcontainers.Container
 	}, backoff.WithMaxRetries(bo, maxRetries))
 
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to create connection after retries")
+		return nil, nil, errors.Wrap(err, "failed to create connection after retries")
 	}
 
-	return DB, nil
-}
-
-// setupSQLMock initializes and returns sqlmock instance.
-func setupSQLMock() (sqlmock.Sqlmock, error) {
-	_, mock, err := sqlmock.New()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to create sqlmock")
-	}
-	return mock, nil
+	return DB, config, nil
 }
 
 // loadSeed inserts dummy data into the database tables for testing purposes.
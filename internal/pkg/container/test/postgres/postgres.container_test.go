@@ -10,7 +10,7 @@ import (
 )
 
 func Test_ORM_Container(t *testing.T) {
-	gorm, _, err := Start(context.Background(), t)
+	gorm, err := Start(context.Background(), t)
 	require.NoError(t, err)
 
 	assert.NotNil(t, gorm)
@@ -18,7 +18,7 @@ func Test_ORM_Container(t *testing.T) {
 
 func TestStartUsesDefaultPostgresOptions(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -29,7 +29,7 @@ func TestStartUsesDefaultPostgresOptions(t *testing.T) {
 
 func TestStartCleansUpContainerAfterTestCompletes(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -40,7 +40,7 @@ func TestStartCleansUpContainerAfterTestCompletes(t *testing.T) {
 
 func TestStartEstablishesGORMConnection(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
@@ -55,7 +55,7 @@ func TestStartHandlesContextCancellationOrTimeout(t *testing.T) {
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Millisecond)
 	defer cancel()
 
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err == nil {
 		t.Fatal("expected an error, got nil")
 	}
@@ -66,7 +66,7 @@ func TestStartHandlesContextCancellationOrTimeout(t *testing.T) {
 
 func TestStartSuccessfullyStartsPostgresContainer(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -77,7 +77,7 @@ func TestStartSuccessfullyStartsPostgresContainer(t *testing.T) {
 
 func TestConfiguresContainerEnvironmentVariablesForPostgreSQL(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -88,7 +88,7 @@ func TestConfiguresContainerEnvironmentVariablesForPostgreSQL(t *testing.T) {
 
 func TestMapsContainerPortsCorrectlyForHostAccess(t *testing.T) {
 	ctx := context.Background()
-	db, _, err := Start(ctx, t)
+	db, err := Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
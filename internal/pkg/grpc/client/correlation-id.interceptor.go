@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+
+	grpc2 "github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithCorrelationID returns a dial option that forwards the correlation ID of the calling context,
+// as set by the HTTP CorrelationIdMiddleware or the server interceptors, to the called server.
+func WithCorrelationID() grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryCorrelationIDInterceptor())
+}
+
+// WithStreamCorrelationID is the stream counterpart of WithCorrelationID.
+func WithStreamCorrelationID() grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamCorrelationIDInterceptor())
+}
+
+// UnaryCorrelationIDInterceptor adds the correlation ID stored in the call's context to its outgoing metadata.
+func UnaryCorrelationIDInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoingCorrelationID(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamCorrelationIDInterceptor adds the correlation ID stored in the stream's context to its outgoing metadata.
+func StreamCorrelationIDInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoingCorrelationID(ctx), desc, cc, method, opts...)
+	}
+}
+
+func outgoingCorrelationID(ctx context.Context) context.Context {
+	id, ok := middleware.CorrelationIDFromContext(ctx)
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, grpc2.CorrelationIDMetadataKey, id)
+}
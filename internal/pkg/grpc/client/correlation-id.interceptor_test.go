@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCorrelationIDPropagatesFromClientToServerHandler(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, listener.Close())
+	config := &server.Config{Host: "localhost", Port: port, Insecure: true}
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", mock.Anything, mock.Anything).Maybe()
+	log.On("Infof", mock.Anything).Maybe()
+
+	seen := make(chan string, 1)
+	srv := server.NewGrpcServer(log, config)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- srv.RunGrpcServer(ctx, func(s *grpc.Server) {
+			s.RegisterService(&grpc.ServiceDesc{
+				ServiceName: "test.Correlated",
+				HandlerType: (*any)(nil),
+				Methods: []grpc.MethodDesc{{
+					MethodName: "Call",
+					Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+						if err := dec(&healthpb.HealthCheckRequest{}); err != nil {
+							return nil, err
+						}
+						info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Correlated/Call"}
+						return interceptor(ctx, nil, info, func(ctx context.Context, _ any) (any, error) {
+							id, _ := middleware.CorrelationIDFromContext(ctx)
+							seen <- id
+							return &healthpb.HealthCheckResponse{}, nil
+						})
+					},
+				}},
+			}, struct{}{})
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+
+	client, err := NewGrpcClient(config, WithCorrelationID())
+	require.NoError(t, err)
+	defer client.Close()
+
+	callCtx, cancelCall := context.WithTimeout(middleware.ContextWithCorrelationID(context.Background(), "abc-123"), 2*time.Second)
+	defer cancelCall()
+	var header metadata.MD
+	err = client.GetGrpcConnection().Invoke(callCtx, "/test.Correlated/Call", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{},
+		grpc.WaitForReady(true), grpc.Header(&header))
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", <-seen)
+	assert.Equal(t, []string{"abc-123"}, header.Get(server.CorrelationIDMetadataKey))
+}
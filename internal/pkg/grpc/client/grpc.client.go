@@ -1,11 +1,12 @@
 package client
 
 import (
+	"context"
 	"fmt"
 
 	grpc2 "github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/connectivity"
 )
 
 // Client interface defines methods for working with gRPC client connections.
@@ -13,6 +14,11 @@ import (
 //go:generate mockery --name Client
 type Client interface {
 	GetGrpcConnection() *grpc.ClientConn
+	// State returns the current connectivity state of the connection.
+	State() connectivity.State
+	// WaitForReady starts connecting if the connection is idle and blocks until it is Ready
+	// or ctx is done.
+	WaitForReady(ctx context.Context) error
 	Close() error
 }
 
@@ -23,21 +29,57 @@ type grpcClient struct {
 // NewGrpcClient creates a new gRPC client connection to the specified host and port.
 //
 // The function takes a pointer to a grpc2.Config struct as a parameter, which contains
-// the host and port information for the gRPC server.
+// the host and port information for the gRPC server and how to secure the connection:
+// TLS by default, or plaintext only when Insecure is set. Additional dial options, such as WithRetry,
+// are applied after the defaults.
 //
 // It returns a Client interface and an error.
 // If the connection is successfully established, the Client interface will be
 // implemented by the grpcClient struct, and the error will be nil.
 // If an error occurs during the connection establishment, the Client interface will be nil,
 // and the error will contain the details of the failure.
-func NewGrpcClient(config *grpc2.Config) (Client, error) {
+func NewGrpcClient(config *grpc2.Config, opts ...grpc.DialOption) (Client, error) {
+	conn, err := newConnection(config, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &grpcClient{conn: conn}, nil
+}
+
+// newConnection creates a connection to the server described by config.
+func newConnection(config *grpc2.Config, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
 	address := fmt.Sprintf("%s:%s", config.Host, config.Port)
-	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	dialOptions, err := credentialOptions(config)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credentials for gRPC server at %s: %w", address, err)
+	}
+	if config.WaitForReady {
+		dialOptions = append(dialOptions, grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	}
+
+	conn, err := grpc.NewClient(address, append(dialOptions, opts...)...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to gRPC server at %s: %w", address, err)
 	}
+	return conn, nil
+}
+
+// Dial creates a gRPC client like NewGrpcClient and blocks until the connection is Ready.
+//
+// Use a context with a timeout to bound the wait; if ctx is done first the connection is closed
+// and an error identifying the address is returned.
+func Dial(ctx context.Context, config *grpc2.Config, opts ...grpc.DialOption) (Client, error) {
+	client, err := NewGrpcClient(config, opts...)
+	if err != nil {
+		return nil, err
+	}
 
-	return &grpcClient{conn: conn}, nil
+	if err := client.WaitForReady(ctx); err != nil {
+		_ = client.Close()
+		return nil, fmt.Errorf("gRPC server at %s:%s is not reachable: %w", config.Host, config.Port, err)
+	}
+	return client, nil
 }
 
 // GetGrpcConnection returns the gRPC client connection.
@@ -45,6 +87,25 @@ func (g *grpcClient) GetGrpcConnection() *grpc.ClientConn {
 	return g.conn
 }
 
+// State returns the current connectivity state of the connection.
+func (g *grpcClient) State() connectivity.State {
+	return g.conn.GetState()
+}
+
+// WaitForReady starts connecting if the connection is idle and blocks until it is Ready or ctx is done.
+func (g *grpcClient) WaitForReady(ctx context.Context) error {
+	g.conn.Connect()
+	for {
+		state := g.conn.GetState()
+		if state == connectivity.Ready {
+			return nil
+		}
+		if !g.conn.WaitForStateChange(ctx, state) {
+			return fmt.Errorf("connection is %s: %w", state, ctx.Err())
+		}
+	}
+}
+
 // Close closes the gRPC client connection and releases all associated resources.
 //
 // It returns an error if the connection cannot be closed successfully.
@@ -1,12 +1,17 @@
 package client
 
 import (
+	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/NekKkMirror/go-app/internal/pkg/grpc/client/mocks"
 	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	google_golang_orggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 func TestNewGrpcClient(t *testing.T) {
@@ -21,6 +26,57 @@ func TestNewGrpcClient(t *testing.T) {
 	assert.NotNil(t, client)
 }
 
+// reservePort returns the address of a port that is free on localhost, without a server on it.
+func reservePort(t *testing.T) *server.Config {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+	return &server.Config{Host: host, Port: port, Insecure: true}
+}
+
+func TestDialBlocksUntilServerIsReachable(t *testing.T) {
+	config := reservePort(t)
+	const startDelay = 200 * time.Millisecond
+
+	s := google_golang_orggrpc.NewServer()
+	t.Cleanup(s.Stop)
+	go func() {
+		time.Sleep(startDelay)
+		listener, err := net.Listen("tcp", net.JoinHostPort(config.Host, config.Port))
+		if err != nil {
+			t.Errorf("failed to start the server: %v", err)
+			return
+		}
+		_ = s.Serve(listener)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	client, err := Dial(ctx, config)
+
+	require.NoError(t, err)
+	defer client.Close()
+	assert.GreaterOrEqual(t, time.Since(start), startDelay)
+	assert.Equal(t, connectivity.Ready, client.State())
+}
+
+func TestDialFailsWhenTimeoutFires(t *testing.T) {
+	config := reservePort(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	client, err := Dial(ctx, config)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Nil(t, client)
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
 func TestGrpcClient_GetGrpcConnection(t *testing.T) {
 	mockClient := mocks.NewClient(t)
 	expectedConn := &google_golang_orggrpc.ClientConn{}
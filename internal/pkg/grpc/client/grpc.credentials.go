@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	grpc2 "github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// credentialOptions returns the dial options securing the connection described by config.
+func credentialOptions(config *grpc2.Config) ([]grpc.DialOption, error) {
+	if config.Insecure {
+		if config.Token != "" {
+			return nil, errors.New("a bearer token cannot be sent over an insecure connection")
+		}
+		return []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, nil
+	}
+
+	tlsConfig := &tls.Config{ServerName: config.ServerName, MinVersion: tls.VersionTLS12}
+	if config.CAFile != "" {
+		pem, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the CA file: %w", err)
+		}
+		rootCAs := x509.NewCertPool()
+		if !rootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", config.CAFile)
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+
+	opts := []grpc.DialOption{grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig))}
+	if config.Token != "" {
+		opts = append(opts, grpc.WithPerRPCCredentials(bearerToken(config.Token)))
+	}
+	return opts, nil
+}
+
+// bearerToken sends a static token in the authorization metadata of every call.
+type bearerToken string
+
+// GetRequestMetadata returns the authorization header for a call.
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity reports that the token must only be sent over TLS.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return true
+}
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// writeSelfSignedCertificate writes a self-signed certificate for localhost and its key to dir.
+// The certificate doubles as the CA that clients trust.
+func writeSelfSignedCertificate(t *testing.T, dir string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return certFile, keyFile
+}
+
+// startTLSServer runs a TLS gRPC server until the test ends and returns the file of the certificate
+// it serves. Authorization metadata received by the server is sent on authorization.
+func startTLSServer(t *testing.T) (config *server.Config, certFile string, authorization <-chan string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, listener.Close())
+
+	certFile, keyFile := writeSelfSignedCertificate(t, t.TempDir())
+	config = &server.Config{Host: "localhost", Port: port, CertFile: certFile, KeyFile: keyFile}
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", mock.Anything, mock.Anything).Maybe()
+	log.On("Infof", mock.Anything).Maybe()
+
+	received := make(chan string, 1)
+	captureAuthorization := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		if values := md.Get("authorization"); len(values) > 0 {
+			received <- values[0]
+		}
+		return handler(ctx, req)
+	}
+	srv := server.NewGrpcServer(log, config, grpc.ChainUnaryInterceptor(captureAuthorization))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunGrpcServer(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", net.JoinHostPort(config.Host, config.Port))
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+
+	return &server.Config{Host: config.Host, Port: config.Port}, certFile, received
+}
+
+func checkHealth(t *testing.T, config *server.Config) error {
+	t.Helper()
+	client, err := NewGrpcClient(config)
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(client.GetGrpcConnection()).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestNewGrpcClientConnectsOverTLS(t *testing.T) {
+	config, certFile, authorization := startTLSServer(t)
+	config.CAFile = certFile
+	config.Token = "secret"
+
+	assert.NoError(t, checkHealth(t, config))
+	assert.Equal(t, "Bearer secret", <-authorization)
+}
+
+func TestNewGrpcClientRejectsServerSignedByUnknownCA(t *testing.T) {
+	config, _, _ := startTLSServer(t)
+	config.CAFile, _ = writeSelfSignedCertificate(t, t.TempDir())
+
+	assert.Error(t, checkHealth(t, config))
+}
+
+func TestNewGrpcClientRefusesTokenOverInsecureConnection(t *testing.T) {
+	_, err := NewGrpcClient(&server.Config{Host: "localhost", Port: "50051", Insecure: true, Token: "secret"})
+
+	assert.Error(t, err)
+}
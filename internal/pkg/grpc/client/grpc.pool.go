@@ -0,0 +1,99 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	grpc2 "github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// Pool maintains a fixed number of connections to one server and hands them out round-robin,
+// spreading calls over several HTTP/2 connections.
+type Pool struct {
+	config *grpc2.Config
+	opts   []grpc.DialOption
+
+	mu     sync.Mutex
+	conns  []*grpc.ClientConn
+	next   int
+	closed bool
+}
+
+// NewPool creates a pool of size connections to the server described by config.
+// The dial options are applied to every connection like in NewGrpcClient.
+func NewPool(config *grpc2.Config, size int, opts ...grpc.DialOption) (*Pool, error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("pool size must be positive, got %d", size)
+	}
+
+	p := &Pool{config: config, opts: opts, conns: make([]*grpc.ClientConn, 0, size)}
+	for range size {
+		conn, err := newConnection(config, opts...)
+		if err != nil {
+			_ = p.Close()
+			return nil, err
+		}
+		p.conns = append(p.conns, conn)
+	}
+	return p, nil
+}
+
+// Get returns the next connection in round-robin order. A connection that has been shut down or is failing
+// to connect is replaced by a new one before it is handed out, and closed once it is out of rotation.
+func (p *Pool) Get() (*grpc.ClientConn, error) {
+	conn, stale, err := p.pick()
+	if stale != nil {
+		_ = stale.Close()
+	}
+	return conn, err
+}
+
+// pick advances the round robin and swaps an unusable connection for a new one, returning the one it replaced.
+func (p *Pool) pick() (conn, stale *grpc.ClientConn, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, nil, errors.New("connection pool is closed")
+	}
+
+	i := p.next
+	p.next = (p.next + 1) % len(p.conns)
+
+	conn = p.conns[i]
+	if state := conn.GetState(); state != connectivity.Shutdown && state != connectivity.TransientFailure {
+		return conn, nil, nil
+	}
+
+	replacement, err := newConnection(p.config, p.opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	p.conns[i] = replacement
+	return replacement, conn, nil
+}
+
+// Size returns the number of connections in the pool.
+func (p *Pool) Size() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.conns)
+}
+
+// Close closes every connection of the pool. Get fails afterwards.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	var errs []error
+	for _, conn := range p.conns {
+		if err := conn.Close(); err != nil && !errors.Is(err, grpc.ErrClientConnClosing) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
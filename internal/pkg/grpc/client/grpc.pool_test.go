@@ -0,0 +1,127 @@
+package client
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func invokeOn(t *testing.T, conn *grpc.ClientConn) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := conn.Invoke(ctx, flakyMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	require.NoError(t, err)
+}
+
+func TestPoolDistributesCallsRoundRobin(t *testing.T) {
+	config, calls := startFlakyServer(t, 0, codes.OK)
+	pool, err := NewPool(config, 3)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	used := make([]*grpc.ClientConn, 0, 6)
+	for range 6 {
+		conn, err := pool.Get()
+		require.NoError(t, err)
+		invokeOn(t, conn)
+		used = append(used, conn)
+	}
+
+	assert.Equal(t, int32(6), calls.Load())
+	assert.NotSame(t, used[0], used[1])
+	assert.NotSame(t, used[1], used[2])
+	assert.NotSame(t, used[0], used[2])
+	assert.Equal(t, used[:3], used[3:])
+}
+
+func TestPoolReplacesDeadConnection(t *testing.T) {
+	config, _ := startFlakyServer(t, 0, codes.OK)
+	pool, err := NewPool(config, 2)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	dead, err := pool.Get()
+	require.NoError(t, err)
+	require.NoError(t, dead.Close())
+	_, err = pool.Get()
+	require.NoError(t, err)
+
+	replacement, err := pool.Get()
+	require.NoError(t, err)
+
+	assert.NotSame(t, dead, replacement)
+	assert.Equal(t, 2, pool.Size())
+	invokeOn(t, replacement)
+}
+
+// waitForState blocks until conn reaches the target state.
+func waitForState(t *testing.T, conn *grpc.ClientConn, target connectivity.State) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for state := conn.GetState(); state != target; state = conn.GetState() {
+		require.True(t, conn.WaitForStateChange(ctx, state), "expected the connection to become %s, got %s", target, state)
+	}
+}
+
+func TestPoolReplacesConnectionsToStoppedServer(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	s := grpc.NewServer()
+	go func() { _ = s.Serve(listener) }()
+
+	pool, err := NewPool(&server.Config{Host: host, Port: port, Insecure: true}, 1)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	failing, err := pool.Get()
+	require.NoError(t, err)
+	failing.Connect()
+	waitForState(t, failing, connectivity.Ready)
+
+	s.Stop()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.True(t, failing.WaitForStateChange(ctx, connectivity.Ready), "expected the connection to drop")
+	failing.Connect()
+	waitForState(t, failing, connectivity.TransientFailure)
+
+	replacement, err := pool.Get()
+	require.NoError(t, err)
+
+	assert.NotSame(t, failing, replacement)
+	assert.Equal(t, connectivity.Shutdown, failing.GetState(), "expected the replaced connection to be closed")
+	assert.NotEqual(t, connectivity.Shutdown, replacement.GetState())
+	assert.Equal(t, 1, pool.Size())
+}
+
+func TestPoolRejectsGetAfterClose(t *testing.T) {
+	config, _ := startFlakyServer(t, 0, codes.OK)
+	pool, err := NewPool(config, 1)
+	require.NoError(t, err)
+
+	require.NoError(t, pool.Close())
+	_, err = pool.Get()
+
+	assert.Error(t, err)
+}
+
+func TestNewPoolRejectsInvalidSize(t *testing.T) {
+	config, _ := startFlakyServer(t, 0, codes.OK)
+
+	_, err := NewPool(config, 0)
+
+	assert.Error(t, err)
+}
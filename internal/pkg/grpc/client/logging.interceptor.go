@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// WithTracing returns a dial option that records an OpenTelemetry span for every call and
+// propagates the span context to the server.
+func WithTracing(opts ...otelgrpc.Option) grpc.DialOption {
+	return grpc.WithStatsHandler(otelgrpc.NewClientHandler(opts...))
+}
+
+// WithLogging returns a dial option that logs every unary call through log.
+func WithLogging(log logger.ILogger) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(UnaryLoggingInterceptor(log))
+}
+
+// WithStreamLogging returns a dial option that logs every stream through log.
+func WithStreamLogging(log logger.ILogger) grpc.DialOption {
+	return grpc.WithChainStreamInterceptor(StreamLoggingInterceptor(log))
+}
+
+// UnaryLoggingInterceptor logs the method, status code and duration of every unary call.
+// Failed calls are logged at error level together with the error, successful ones at info level.
+func UnaryLoggingInterceptor(log logger.ILogger) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		logCall(log, method, time.Since(start), err)
+		return err
+	}
+}
+
+// StreamLoggingInterceptor logs the method, status code and duration of every stream once it ends,
+// or immediately when it cannot be opened.
+func StreamLoggingInterceptor(log logger.ILogger) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			logCall(log, method, time.Since(start), err)
+			return nil, err
+		}
+		return &loggedStream{ClientStream: stream, log: log, method: method, start: start}, nil
+	}
+}
+
+// loggedStream logs the outcome of a stream the first time receiving from it fails,
+// which includes io.EOF at its regular end.
+type loggedStream struct {
+	grpc.ClientStream
+	log    logger.ILogger
+	method string
+	start  time.Time
+	once   sync.Once
+}
+
+// RecvMsg receives the next message and logs the stream's outcome once it has ended.
+func (s *loggedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.once.Do(func() {
+			if errors.Is(err, io.EOF) {
+				logCall(s.log, s.method, time.Since(s.start), nil)
+				return
+			}
+			logCall(s.log, s.method, time.Since(s.start), err)
+		})
+	}
+	return err
+}
+
+func logCall(log logger.ILogger, method string, duration time.Duration, err error) {
+	code := status.Code(err)
+	if err != nil {
+		log.Errorf("%s %s %s: %v", method, code, duration, err)
+		return
+	}
+	log.Infof("%s %s %s", method, code, duration)
+}
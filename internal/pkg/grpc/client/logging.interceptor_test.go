@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/test.Service/Method"
+
+func TestUnaryLoggingInterceptorLogsSuccessfulCall(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Infof", "%s %s %s", testMethod, codes.OK, mock.Anything).Once()
+
+	err := UnaryLoggingInterceptor(log)(context.Background(), testMethod, nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return nil })
+
+	assert.NoError(t, err)
+}
+
+func TestUnaryLoggingInterceptorLogsFailedCall(t *testing.T) {
+	log := mocks.NewILogger(t)
+	callErr := status.Error(codes.PermissionDenied, "denied")
+	log.On("Errorf", "%s %s %s: %v", testMethod, codes.PermissionDenied, mock.Anything, callErr).Once()
+
+	err := UnaryLoggingInterceptor(log)(context.Background(), testMethod, nil, nil, nil,
+		func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error { return callErr })
+
+	assert.Equal(t, callErr, err)
+}
+
+// endedStream is a client stream whose messages are exhausted.
+type endedStream struct {
+	grpc.ClientStream
+}
+
+func (endedStream) RecvMsg(any) error { return io.EOF }
+
+func TestStreamLoggingInterceptorLogsOnceWhenStreamEnds(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Infof", "%s %s %s", testMethod, codes.OK, mock.Anything).Once()
+
+	stream, err := StreamLoggingInterceptor(log)(context.Background(), &grpc.StreamDesc{}, nil, testMethod,
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return endedStream{}, nil
+		})
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+	assert.ErrorIs(t, stream.RecvMsg(nil), io.EOF)
+}
+
+func TestWithTracingCreatesClientSpanAndPropagatesIt(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	traceparent := make(chan string, 1)
+	s := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		traceparent <- strings.Join(md.Get("traceparent"), ",")
+		return handler(ctx, req)
+	}))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go func() { _ = s.Serve(listener) }()
+	t.Cleanup(s.Stop)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	client, err := NewGrpcClient(&server.Config{Host: host, Port: port, Insecure: true}, WithTracing(
+		otelgrpc.WithTracerProvider(provider),
+		otelgrpc.WithPropagators(propagation.TraceContext{}),
+	))
+	require.NoError(t, err)
+	defer client.Close()
+
+	_, err = healthpb.NewHealthClient(client.GetGrpcConnection()).Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "grpc.health.v1.Health/Check", spans[0].Name)
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind)
+	assert.Contains(t, <-traceparent, spans[0].SpanContext.TraceID().String())
+}
@@ -3,8 +3,13 @@
 package mocks
 
 import (
-	mock "github.com/stretchr/testify/mock"
+	context "context"
+
+	connectivity "google.golang.org/grpc/connectivity"
+
 	grpc "google.golang.org/grpc"
+
+	mock "github.com/stretchr/testify/mock"
 )
 
 // Client is an autogenerated mock type for the Client type
@@ -50,6 +55,42 @@ func (_m *Client) GetGrpcConnection() *grpc.ClientConn {
 	return r0
 }
 
+// State provides a mock function with given fields:
+func (_m *Client) State() connectivity.State {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for State")
+	}
+
+	var r0 connectivity.State
+	if rf, ok := ret.Get(0).(func() connectivity.State); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(connectivity.State)
+	}
+
+	return r0
+}
+
+// WaitForReady provides a mock function with given fields: ctx
+func (_m *Client) WaitForReady(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WaitForReady")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewClient creates a new instance of Client. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewClient(t interface {
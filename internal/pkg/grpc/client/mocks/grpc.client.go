@@ -1,8 +1,11 @@
 package mocks
 
 import (
+	"context"
+
 	"github.com/stretchr/testify/mock"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 type GrpcClientMock struct {
@@ -37,6 +40,32 @@ func (_m *GrpcClientMock) GetGrpcConnection() *grpc.ClientConn {
 	return r0
 }
 
+func (_m *GrpcClientMock) State() connectivity.State {
+	ret := _m.Called()
+
+	var r0 connectivity.State
+	if rf, ok := ret.Get(0).(func() connectivity.State); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(connectivity.State)
+	}
+
+	return r0
+}
+
+func (_m *GrpcClientMock) WaitForReady(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockNewGrpcClientConstructor interface {
 	mock.TestingT
 	Cleanup(func())
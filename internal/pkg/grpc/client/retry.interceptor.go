@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Default retry policy, used for RetryConfig fields left at zero.
+const (
+	defaultRetryMaxAttempts    = 3
+	defaultRetryInitialBackoff = 100 * time.Millisecond
+	defaultRetryMaxBackoff     = 2 * time.Second
+)
+
+// RetryConfig controls how failed unary calls are retried.
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts including the first call; zero means 3.
+	MaxAttempts int
+	// InitialBackoff is the wait before the first retry, doubled after every further attempt up to MaxBackoff.
+	// Zero values fall back to 100ms and 2s.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// RetryableCodes lists the status codes that are retried; empty means only codes.Unavailable.
+	RetryableCodes []codes.Code
+}
+
+// WithRetry returns a dial option that retries failed unary calls according to cfg.
+func WithRetry(cfg RetryConfig) grpc.DialOption {
+	return grpc.WithChainUnaryInterceptor(RetryUnaryInterceptor(cfg))
+}
+
+// RetryUnaryInterceptor retries unary calls that fail with a retryable status code, waiting with
+// exponential backoff between attempts. It gives up when the attempts are exhausted or the call's
+// context is done, returning the last error.
+func RetryUnaryInterceptor(cfg RetryConfig) grpc.UnaryClientInterceptor {
+	cfg = cfg.withDefaults()
+
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		backoff := cfg.InitialBackoff
+		for attempt := 1; ; attempt++ {
+			err := invoker(ctx, method, req, reply, cc, opts...)
+			if err == nil || attempt >= cfg.MaxAttempts || !slices.Contains(cfg.RetryableCodes, status.Code(err)) {
+				return err
+			}
+
+			timer := time.NewTimer(backoff)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+			backoff = min(2*backoff, cfg.MaxBackoff)
+		}
+	}
+}
+
+func (cfg RetryConfig) withDefaults() RetryConfig {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaultRetryMaxAttempts
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultRetryInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultRetryMaxBackoff
+	}
+	if len(cfg.RetryableCodes) == 0 {
+		cfg.RetryableCodes = []codes.Code{codes.Unavailable}
+	}
+	return cfg
+}
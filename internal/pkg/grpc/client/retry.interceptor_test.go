@@ -0,0 +1,105 @@
+package client
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+const flakyMethod = "/test.Flaky/Call"
+
+// startFlakyServer serves a unary method that fails with code for the first failures calls and
+// then succeeds. It returns the server address and the number of calls received so far.
+func startFlakyServer(t *testing.T, failures int32, code codes.Code) (*server.Config, *atomic.Int32) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+
+	calls := &atomic.Int32{}
+	s := grpc.NewServer()
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Flaky",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Call",
+			Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				if err := dec(&healthpb.HealthCheckRequest{}); err != nil {
+					return nil, err
+				}
+				if calls.Add(1) <= failures {
+					return nil, status.Error(code, "try again")
+				}
+				return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+			},
+		}},
+	}, struct{}{})
+	go func() { _ = s.Serve(listener) }()
+	t.Cleanup(s.Stop)
+
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return &server.Config{Host: host, Port: port, Insecure: true}, calls
+}
+
+func invokeFlaky(t *testing.T, config *server.Config, retry RetryConfig) error {
+	t.Helper()
+	client, err := NewGrpcClient(config, WithRetry(retry))
+	require.NoError(t, err)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return client.GetGrpcConnection().Invoke(ctx, flakyMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+}
+
+func TestRetryUnaryInterceptorRetriesUntilSuccess(t *testing.T) {
+	config, calls := startFlakyServer(t, 2, codes.Unavailable)
+
+	err := invokeFlaky(t, config, RetryConfig{MaxAttempts: 3, InitialBackoff: time.Millisecond})
+
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestRetryUnaryInterceptorGivesUpAfterMaxAttempts(t *testing.T) {
+	config, calls := startFlakyServer(t, 5, codes.Unavailable)
+
+	err := invokeFlaky(t, config, RetryConfig{MaxAttempts: 2, InitialBackoff: time.Millisecond})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, int32(2), calls.Load())
+}
+
+func TestRetryUnaryInterceptorDoesNotRetryOtherCodes(t *testing.T) {
+	config, calls := startFlakyServer(t, 1, codes.InvalidArgument)
+
+	err := invokeFlaky(t, config, RetryConfig{InitialBackoff: time.Millisecond})
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestRetryUnaryInterceptorStopsWhenContextIsDone(t *testing.T) {
+	interceptor := RetryUnaryInterceptor(RetryConfig{MaxAttempts: 5, InitialBackoff: time.Hour})
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	attempts := 0
+	err := interceptor(ctx, flakyMethod, nil, nil, nil, func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		attempts++
+		return status.Error(codes.Unavailable, "down")
+	})
+
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Equal(t, 1, attempts)
+}
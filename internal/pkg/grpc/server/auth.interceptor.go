@@ -0,0 +1,93 @@
+package server
+
+import (
+	"context"
+	"slices"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// claimsKey is the context key under which the authenticated caller's claims are stored.
+type claimsKey struct{}
+
+// ClaimsFromContext returns the JWT claims stored by the auth interceptors, and whether the call was authenticated.
+func ClaimsFromContext(ctx context.Context) (*generates.JWTAccessClaims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*generates.JWTAccessClaims)
+	return claims, ok
+}
+
+// UnaryAuthInterceptor validates the bearer token in the authorization metadata of every unary call,
+// the same way the HTTP ValidateBearerToken middleware does, and stores its claims in the context.
+// Calls without a valid token are rejected with codes.Unauthenticated. Methods listed in skipMethods,
+// such as the health check, are not authenticated.
+func UnaryAuthInterceptor(skipMethods ...string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if slices.Contains(skipMethods, info.FullMethod) {
+			return handler(ctx, req)
+		}
+		ctx, err := authenticate(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamAuthInterceptor validates the bearer token in the authorization metadata of every stream,
+// the same way the HTTP ValidateBearerToken middleware does, and stores its claims in the stream's context.
+// Streams without a valid token are rejected with codes.Unauthenticated. Methods listed in skipMethods
+// are not authenticated.
+func StreamAuthInterceptor(skipMethods ...string) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if slices.Contains(skipMethods, info.FullMethod) {
+			return handler(srv, ss)
+		}
+		ctx, err := authenticate(ss.Context())
+		if err != nil {
+			return err
+		}
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate validates the bearer token of the incoming call and returns a context carrying its claims.
+func authenticate(ctx context.Context) (context.Context, error) {
+	if middleware.IsTestEnvironment() {
+		return ctx, nil
+	}
+
+	var authToken string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 {
+		authToken = middleware.ExtractTokenFromHeader(values[0])
+	}
+	if authToken == "" {
+		return nil, status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+
+	token, err := middleware.ParseJWT(authToken)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	claims, ok := token.Claims.(*generates.JWTAccessClaims)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "unexpected token claims")
+	}
+	return context.WithValue(ctx, claimsKey{}, claims), nil
+}
+
+// contextServerStream overrides the context of a server stream.
+type contextServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the overridden context.
+func (s *contextServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,101 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/golang-jwt/jwt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func signToken(t *testing.T, secret, subject string) string {
+	t.Helper()
+	claims := &generates.JWTAccessClaims{StandardClaims: jwt.StandardClaims{
+		Subject:   subject,
+		ExpiresAt: time.Now().Add(time.Hour).Unix(),
+	}}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+// callAuthenticated runs the unary auth interceptor with the given authorization metadata
+// and returns the claims seen by the handler.
+func callAuthenticated(authorization string, skipMethods ...string) (*generates.JWTAccessClaims, error) {
+	ctx := context.Background()
+	if authorization != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", authorization))
+	}
+
+	var claims *generates.JWTAccessClaims
+	_, err := UnaryAuthInterceptor(skipMethods...)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) {
+			claims, _ = ClaimsFromContext(ctx)
+			return nil, nil
+		})
+	return claims, err
+}
+
+func TestUnaryAuthInterceptorAcceptsValidToken(t *testing.T) {
+	claims, err := callAuthenticated("Bearer " + signToken(t, "secret", "user-42"))
+
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, "user-42", claims.Subject)
+}
+
+func TestUnaryAuthInterceptorRejectsMissingToken(t *testing.T) {
+	_, err := callAuthenticated("")
+
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUnaryAuthInterceptorRejectsInvalidToken(t *testing.T) {
+	_, err := callAuthenticated("Bearer " + signToken(t, "other-secret", "user-42"))
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = callAuthenticated("Basic dXNlcjpwYXNz")
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestUnaryAuthInterceptorSkipsListedMethods(t *testing.T) {
+	claims, err := callAuthenticated("", testMethod)
+
+	assert.NoError(t, err)
+	assert.Nil(t, claims)
+}
+
+// contextStream is a server stream with a fixed context.
+type contextStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s contextStream) Context() context.Context { return s.ctx }
+
+func TestStreamAuthInterceptorStoresClaimsInStreamContext(t *testing.T) {
+	md := metadata.Pairs("authorization", "Bearer "+signToken(t, "secret", "user-42"))
+	stream := contextStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+
+	var claims *generates.JWTAccessClaims
+	err := StreamAuthInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(srv any, ss grpc.ServerStream) error {
+			claims, _ = ClaimsFromContext(ss.Context())
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.NotNil(t, claims)
+	assert.Equal(t, "user-42", claims.Subject)
+
+	err = StreamAuthInterceptor()(nil, contextStream{ctx: context.Background()}, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(srv any, ss grpc.ServerStream) error { return nil })
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"strings"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// CorrelationIDMetadataKey is the gRPC metadata key carrying the correlation ID,
+// the lower-cased form of the HTTP X-Correlation-Id header.
+var CorrelationIDMetadataKey = strings.ToLower(echo.HeaderXCorrelationID)
+
+// UnaryCorrelationIDInterceptor takes the correlation ID from the incoming metadata, or generates a new one,
+// sends it back in the response header and stores it in the context under the same key as the HTTP
+// CorrelationIdMiddleware, so middleware.CorrelationIDFromContext works for both transports.
+func UnaryCorrelationIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(withCorrelationID(ctx), req)
+	}
+}
+
+// StreamCorrelationIDInterceptor is the stream counterpart of UnaryCorrelationIDInterceptor.
+func StreamCorrelationIDInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &contextServerStream{ServerStream: ss, ctx: withCorrelationID(ss.Context())})
+	}
+}
+
+// withCorrelationID stores the call's correlation ID in ctx and announces it in the response header.
+func withCorrelationID(ctx context.Context) context.Context {
+	var id string
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(CorrelationIDMetadataKey); len(values) > 0 {
+		id = values[0]
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(CorrelationIDMetadataKey, id))
+	return middleware.ContextWithCorrelationID(ctx, id)
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func correlationIDSeenByHandler(t *testing.T, ctx context.Context) string {
+	t.Helper()
+	var id string
+	_, err := UnaryCorrelationIDInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) {
+			id, _ = middleware.CorrelationIDFromContext(ctx)
+			return nil, nil
+		})
+	require.NoError(t, err)
+	return id
+}
+
+func TestUnaryCorrelationIDInterceptorUsesIncomingID(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDMetadataKey, "abc-123"))
+
+	assert.Equal(t, "abc-123", correlationIDSeenByHandler(t, ctx))
+}
+
+func TestUnaryCorrelationIDInterceptorGeneratesMissingID(t *testing.T) {
+	first := correlationIDSeenByHandler(t, context.Background())
+	second := correlationIDSeenByHandler(t, context.Background())
+
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+}
+
+func TestStreamCorrelationIDInterceptorStoresIDInStreamContext(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(CorrelationIDMetadataKey, "abc-123"))
+
+	var id string
+	err := StreamCorrelationIDInterceptor()(nil, contextStream{ctx: ctx}, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(srv any, ss grpc.ServerStream) error {
+			id, _ = middleware.CorrelationIDFromContext(ss.Context())
+			return nil
+		})
+
+	require.NoError(t, err)
+	assert.Equal(t, "abc-123", id)
+}
@@ -2,17 +2,24 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"net"
+	"os"
 	"time"
 
 	"github.com/NekKkMirror/go-app/internal/pkg/logger"
 	"github.com/pkg/errors"
 	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/keepalive"
 	"google.golang.org/grpc/reflection"
 )
 
+// Default keepalive parameters, used for Config fields left at zero.
 const (
 	maxConnectionIdle = 5 * time.Minute
 	maxConnectionAge  = 5 * time.Minute
@@ -20,11 +27,51 @@ const (
 	gRPCTimeout       = 15 * time.Second
 )
 
+// gracefulStopTimeout is the default of Config.ShutdownTimeout.
+const gracefulStopTimeout = 10 * time.Second
+
 // Config contains the configuration for the gRPC server.
 type Config struct {
 	Host        string `mapstructure:"host"`
 	Port        string `mapstructure:"port"`
 	Development bool   `mapstructure:"development"`
+	// EnableReflection registers the reflection service; when unset it follows Development.
+	EnableReflection *bool `mapstructure:"enableReflection"`
+
+	// CertFile and KeyFile enable TLS when both are set.
+	CertFile string `mapstructure:"certFile"`
+	KeyFile  string `mapstructure:"keyFile"`
+	// ClientCAFile additionally requires clients to present a certificate signed by this CA (mutual TLS).
+	ClientCAFile string `mapstructure:"clientCAFile"`
+
+	// ShutdownTimeout bounds how long in-flight RPCs may drain once shutdown begins before the server
+	// is stopped forcefully; zero means 10s.
+	ShutdownTimeout time.Duration `mapstructure:"shutdownTimeout"`
+
+	// CallTimeout bounds unary calls that arrive without a client deadline; zero leaves them unbounded.
+	CallTimeout time.Duration `mapstructure:"callTimeout"`
+
+	// MaxRecvMsgSize and MaxSendMsgSize limit message sizes in bytes; zero keeps the gRPC defaults
+	// of 4MB for received and unlimited for sent messages.
+	MaxRecvMsgSize int `mapstructure:"maxRecvMsgSize"`
+	MaxSendMsgSize int `mapstructure:"maxSendMsgSize"`
+
+	// Client settings used by client.NewGrpcClient. Connections use TLS, verified against CAFile or the
+	// system roots when it is empty, unless Insecure is set. ServerName overrides the name checked
+	// against the server certificate, and Token is sent as a bearer token with every call.
+	Insecure   bool   `mapstructure:"insecure"`
+	CAFile     string `mapstructure:"caFile"`
+	ServerName string `mapstructure:"serverName"`
+	Token      string `mapstructure:"token"`
+	// WaitForReady makes client calls wait for the connection to become ready instead of failing
+	// fast while it is connecting.
+	WaitForReady bool `mapstructure:"waitForReady"`
+
+	// Keepalive settings; zero values fall back to 5m idle, 5m age, 10m ping interval and 15s ping timeout.
+	MaxConnectionIdle time.Duration `mapstructure:"maxConnectionIdle"`
+	MaxConnectionAge  time.Duration `mapstructure:"maxConnectionAge"`
+	KeepaliveTime     time.Duration `mapstructure:"keepaliveTime"`
+	KeepaliveTimeout  time.Duration `mapstructure:"keepaliveTimeout"`
 }
 
 // Server wraps the gRPC server along with its configuration and logger.
@@ -32,35 +79,140 @@ type Server struct {
 	Grpc   *grpc.Server
 	Config *Config
 	Log    logger.ILogger
+	// Health is the standard grpc.health.v1 service registered on Grpc.
+	Health *health.Server
+
+	// initErr holds an error raised while building the server, such as unreadable TLS files,
+	// and is returned by RunGrpcServer.
+	initErr error
 }
 
 // NewGrpcServer creates a new gRPC server instance with the provided configuration and logger.
 //
-// It initializes the gRPC server with keepalive parameters and OpenTelemetry instrumentation.
-func NewGrpcServer(log logger.ILogger, config *Config) *Server {
+// The server comes with keepalive, tracing, panic recovery, correlation IDs and the health service,
+// plus the TLS, call timeout and message size settings of config. TLS errors are reported by
+// RunGrpcServer. Additional options, such as the logging interceptors, are applied after the defaults.
+func NewGrpcServer(log logger.ILogger, config *Config, opts ...grpc.ServerOption) *Server {
+	tlsConfig, err := serverTLSConfig(config)
+
 	serverOptions := []grpc.ServerOption{
-		grpc.KeepaliveParams(keepalive.ServerParameters{
-			MaxConnectionIdle: maxConnectionIdle,
-			Timeout:           gRPCTimeout,
-			MaxConnectionAge:  maxConnectionAge,
-			Time:              gRPCTime,
-		}),
+		grpc.KeepaliveParams(keepaliveParams(config)),
 		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(UnaryRecoveryInterceptor(log), UnaryCorrelationIDInterceptor()),
+		grpc.ChainStreamInterceptor(StreamRecoveryInterceptor(log), StreamCorrelationIDInterceptor()),
+	}
+
+	if tlsConfig != nil {
+		serverOptions = append(serverOptions, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if config.CallTimeout > 0 {
+		serverOptions = append(serverOptions, grpc.ChainUnaryInterceptor(UnaryTimeoutInterceptor(config.CallTimeout)))
+	}
+	if config.MaxRecvMsgSize > 0 {
+		serverOptions = append(serverOptions, grpc.MaxRecvMsgSize(config.MaxRecvMsgSize))
+	}
+	if config.MaxSendMsgSize > 0 {
+		serverOptions = append(serverOptions, grpc.MaxSendMsgSize(config.MaxSendMsgSize))
+	}
+
+	s := grpc.NewServer(append(serverOptions, opts...)...)
+
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+
+	return &Server{
+		Grpc:    s,
+		Config:  config,
+		Log:     log,
+		Health:  healthServer,
+		initErr: err,
+	}
+}
+
+// SetServingStatus sets the serving status reported by the health service for the given service.
+// The empty service name refers to the server as a whole.
+func (s *Server) SetServingStatus(service string, status healthpb.HealthCheckResponse_ServingStatus) {
+	s.Health.SetServingStatus(service, status)
+}
+
+// StopServing reports every service as NOT_SERVING so that health probes stop routing traffic
+// to the server. Later status updates are ignored. It is called when shutdown begins.
+func (s *Server) StopServing() {
+	s.Health.Shutdown()
+}
+
+// reflectionEnabled reports whether the reflection service should be registered.
+func (c *Config) reflectionEnabled() bool {
+	if c.EnableReflection != nil {
+		return *c.EnableReflection
+	}
+	return c.Development
+}
+
+// keepaliveParams returns the configured keepalive parameters, using the defaults for unset fields.
+func keepaliveParams(config *Config) keepalive.ServerParameters {
+	return keepalive.ServerParameters{
+		MaxConnectionIdle: durationOrDefault(config.MaxConnectionIdle, maxConnectionIdle),
+		MaxConnectionAge:  durationOrDefault(config.MaxConnectionAge, maxConnectionAge),
+		Time:              durationOrDefault(config.KeepaliveTime, gRPCTime),
+		Timeout:           durationOrDefault(config.KeepaliveTimeout, gRPCTimeout),
+	}
+}
+
+func durationOrDefault(d, fallback time.Duration) time.Duration {
+	if d <= 0 {
+		return fallback
+	}
+	return d
+}
+
+// serverTLSConfig builds the TLS configuration from the certificate files in config.
+// It returns nil when TLS is not configured.
+func serverTLSConfig(config *Config) (*tls.Config, error) {
+	if config.CertFile == "" && config.KeyFile == "" {
+		if config.ClientCAFile != "" {
+			return nil, errors.New("clientCAFile requires certFile and keyFile")
+		}
+		return nil, nil
+	}
+	if config.CertFile == "" || config.KeyFile == "" {
+		return nil, errors.New("both certFile and keyFile are required for TLS")
 	}
 
-	s := grpc.NewServer(serverOptions...)
+	certificate, err := tls.LoadX509KeyPair(config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load the server certificate")
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{certificate}, MinVersion: tls.VersionTLS12}
 
-	return &Server{Grpc: s, Config: config, Log: log}
+	if config.ClientCAFile != "" {
+		pem, err := os.ReadFile(config.ClientCAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to read the client CA file")
+		}
+		clientCAs := x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return nil, errors.Errorf("no certificates found in client CA file %s", config.ClientCAFile)
+		}
+		tlsConfig.ClientCAs = clientCAs
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
 }
 
 // RunGrpcServer starts the gRPC server and listens on the specified host and port.
 //
-// The server runs in a separate goroutine and shuts down gracefully when the provided context is done.
+// When the provided context is done the server stops accepting new RPCs and drains in-flight ones,
+// and RunGrpcServer returns once the shutdown has finished.
 func (s *Server) RunGrpcServer(ctx context.Context, configGrpc ...func(grpcServer *grpc.Server)) error {
+	if s.initErr != nil {
+		return s.initErr
+	}
+
 	address := net.JoinHostPort(s.Config.Host, s.Config.Port)
 	listener, err := net.Listen("tcp", address)
 	if err != nil {
-		return errors.Wrap(err, "net.Listen failed to listen")
+		return errors.Wrapf(err, "failed to listen on %s", address)
 	}
 
 	// Apply additional server configurations if provided
@@ -68,28 +220,60 @@ func (s *Server) RunGrpcServer(ctx context.Context, configGrpc ...func(grpcServe
 		configGrpc[0](s.Grpc)
 	}
 
-	// Register reflection for development mode
-	if s.Config.Development {
+	if s.Config.reflectionEnabled() {
 		reflection.Register(s.Grpc)
 	}
 
-	go s.handleServerShutdown(ctx)
+	serveDone := make(chan struct{})
+	shutdownDone := make(chan struct{})
+	go func() {
+		defer close(shutdownDone)
+		s.handleServerShutdown(ctx, serveDone)
+	}()
 
 	s.Log.Infof("gRPC server listening on port: %s", s.Config.Port)
 
-	if err := s.Grpc.Serve(listener); err != nil {
+	err = s.Grpc.Serve(listener)
+	close(serveDone)
+	// Serve returns as soon as shutdown begins; wait for in-flight RPCs to drain.
+	<-shutdownDone
+
+	if err != nil {
 		s.Log.Errorf("gRPC server serve error: %v", err)
-		return err
+		return errors.Wrapf(err, "gRPC server on %s stopped unexpectedly", address)
 	}
-
 	return nil
 }
 
 // handleServerShutdown listens for context cancellation to shutdown the server gracefully.
-func (s *Server) handleServerShutdown(ctx context.Context) {
-	<-ctx.Done()
+// It returns without shutting down if serveDone is closed first, because the server already stopped serving.
+//
+// In-flight RPCs get Config.ShutdownTimeout to complete, after which the server is stopped forcefully.
+func (s *Server) handleServerShutdown(ctx context.Context, serveDone <-chan struct{}) {
+	select {
+	case <-ctx.Done():
+	case <-serveDone:
+		return
+	}
 	s.Log.Infof("shutting down gRPC server on port: %s", s.Config.Port)
+	s.StopServing()
 
-	s.Grpc.GracefulStop()
-	s.Log.Infof("gRPC server exited properly")
+	stopped := make(chan struct{})
+	go func() {
+		s.Grpc.GracefulStop()
+		close(stopped)
+	}()
+
+	shutdownTimeout := durationOrDefault(s.Config.ShutdownTimeout, gracefulStopTimeout)
+	timer := time.NewTimer(shutdownTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-stopped:
+		s.Log.Infof("gRPC server exited properly")
+	case <-timer.C:
+		s.Log.Warnf("gRPC server did not stop within %s, forcing shutdown", shutdownTimeout)
+		s.Grpc.Stop()
+		<-stopped
+	}
 }
@@ -2,11 +2,34 @@ package server
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/keepalive"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/grpc/status"
 )
 
 func TestRunGrpcServer(t *testing.T) {
@@ -32,3 +55,504 @@ func TestRunGrpcServer(t *testing.T) {
 
 	mockLogger.AssertExpectations(t)
 }
+
+// testCertificates holds PEM files of a test CA and a server certificate it signed,
+// plus a client certificate signed by the same CA.
+type testCertificates struct {
+	CAFile   string
+	CertFile string
+	KeyFile  string
+	CAPool   *x509.CertPool
+	Client   tls.Certificate
+}
+
+func newTestCertificates(t *testing.T) testCertificates {
+	t.Helper()
+	dir := t.TempDir()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: "localhost"},
+			DNSNames:     []string{"localhost"},
+			IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, caCert, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		keyDER, err := x509.MarshalECPrivateKey(key)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	}
+
+	writeFile := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+		return path
+	}
+
+	serverCert, serverKey := issue(2, x509.ExtKeyUsageServerAuth)
+	clientCertPEM, clientKeyPEM := issue(3, x509.ExtKeyUsageClientAuth)
+	clientCert, err := tls.X509KeyPair(clientCertPEM, clientKeyPEM)
+	require.NoError(t, err)
+
+	pool := x509.NewCertPool()
+	pool.AddCert(caCert)
+
+	return testCertificates{
+		CAFile:   writeFile("ca.pem", pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})),
+		CertFile: writeFile("server.pem", serverCert),
+		KeyFile:  writeFile("server-key.pem", serverKey),
+		CAPool:   pool,
+		Client:   clientCert,
+	}
+}
+
+// freePort returns a TCP port that is currently free on localhost.
+func freePort(t *testing.T) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	return port
+}
+
+// newTestServer creates a server whose logger accepts any info and warning logs.
+func newTestServer(t *testing.T, config *Config) *Server {
+	t.Helper()
+	mockLogger := mocks.NewILogger(t)
+	mockLogger.On("Infof", mock.Anything, mock.Anything).Maybe()
+	mockLogger.On("Infof", mock.Anything).Maybe()
+	mockLogger.On("Warnf", mock.Anything, mock.Anything).Maybe()
+	return NewGrpcServer(mockLogger, config)
+}
+
+// startServer runs server until the returned stop function is called or the test ends,
+// and waits until it accepts connections. stop returns the result of RunGrpcServer.
+func startServer(t *testing.T, server *Server, register func(*grpc.Server)) (stop func() error) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.RunGrpcServer(ctx, register)
+	}()
+
+	var (
+		once   sync.Once
+		result error
+	)
+	stop = func() error {
+		once.Do(func() {
+			cancel()
+			result = <-done
+		})
+		return result
+	}
+	t.Cleanup(func() { _ = stop() })
+
+	require.Eventually(t, func() bool {
+		conn, err := net.Dial("tcp", net.JoinHostPort(server.Config.Host, server.Config.Port))
+		if err != nil {
+			return false
+		}
+		_ = conn.Close()
+		return true
+	}, 2*time.Second, 10*time.Millisecond)
+	return stop
+}
+
+// startHealthServer runs a gRPC server until the test ends, exposing its built-in health service.
+func startHealthServer(t *testing.T, config *Config) {
+	t.Helper()
+	stop := startServer(t, newTestServer(t, config), nil)
+	t.Cleanup(func() { assert.NoError(t, stop()) })
+}
+
+func checkHealth(t *testing.T, address string, creds credentials.TransportCredentials) error {
+	t.Helper()
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestRunGrpcServerServesTLS(t *testing.T) {
+	certs := newTestCertificates(t)
+	config := &Config{Host: "localhost", Port: freePort(t), CertFile: certs.CertFile, KeyFile: certs.KeyFile}
+	startHealthServer(t, config)
+	address := net.JoinHostPort(config.Host, config.Port)
+
+	err := checkHealth(t, address, credentials.NewTLS(&tls.Config{RootCAs: certs.CAPool}))
+	assert.NoError(t, err)
+
+	err = checkHealth(t, address, insecure.NewCredentials())
+	assert.Error(t, err, "expected a plaintext client to be rejected")
+}
+
+func TestRunGrpcServerRequiresClientCertificateForMutualTLS(t *testing.T) {
+	certs := newTestCertificates(t)
+	config := &Config{
+		Host:         "localhost",
+		Port:         freePort(t),
+		CertFile:     certs.CertFile,
+		KeyFile:      certs.KeyFile,
+		ClientCAFile: certs.CAFile,
+	}
+	startHealthServer(t, config)
+	address := net.JoinHostPort(config.Host, config.Port)
+
+	err := checkHealth(t, address, credentials.NewTLS(&tls.Config{
+		RootCAs:      certs.CAPool,
+		Certificates: []tls.Certificate{certs.Client},
+	}))
+	assert.NoError(t, err)
+
+	err = checkHealth(t, address, credentials.NewTLS(&tls.Config{RootCAs: certs.CAPool}))
+	assert.Error(t, err, "expected a client without a certificate to be rejected")
+}
+
+func TestRunGrpcServerReportsInvalidTLSConfiguration(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t), CertFile: filepath.Join(t.TempDir(), "missing.pem")}
+
+	err := NewGrpcServer(mocks.NewILogger(t), config).RunGrpcServer(context.Background())
+	assert.ErrorContains(t, err, "both certFile and keyFile are required")
+
+	config.KeyFile = filepath.Join(t.TempDir(), "missing-key.pem")
+	err = NewGrpcServer(mocks.NewILogger(t), config).RunGrpcServer(context.Background())
+	assert.ErrorContains(t, err, "failed to load the server certificate")
+}
+
+// blockingService is a unary RPC that signals when it starts and then waits for release
+// or for its context to be cancelled.
+type blockingService struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+const blockingMethod = "/test.Blocking/Wait"
+
+func (b *blockingService) register(s *grpc.Server) {
+	s.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.Blocking",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{{
+			MethodName: "Wait",
+			Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+				if err := dec(&healthpb.HealthCheckRequest{}); err != nil {
+					return nil, err
+				}
+				close(b.entered)
+				select {
+				case <-b.release:
+					return &healthpb.HealthCheckResponse{Status: healthpb.HealthCheckResponse_SERVING}, nil
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				}
+			},
+		}},
+	}, b)
+}
+
+// callBlocking starts a call to the blocking service and returns a channel with its result.
+func callBlocking(t *testing.T, address string) <-chan error {
+	t.Helper()
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	result := make(chan error, 1)
+	go func() {
+		result <- conn.Invoke(context.Background(), blockingMethod, &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	}()
+	return result
+}
+
+func TestRunGrpcServerDrainsInFlightCallsOnShutdown(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	service := &blockingService{entered: make(chan struct{}), release: make(chan struct{})}
+	stop := startServer(t, newTestServer(t, config), service.register)
+
+	result := callBlocking(t, net.JoinHostPort(config.Host, config.Port))
+	<-service.entered
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- stop() }()
+
+	select {
+	case <-stopped:
+		t.Fatal("expected shutdown to wait for the in-flight call")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(service.release)
+	assert.NoError(t, <-result, "expected the in-flight call to complete")
+	assert.NoError(t, <-stopped)
+}
+
+func TestRunGrpcServerForcesShutdownAfterTimeout(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t), ShutdownTimeout: 50 * time.Millisecond}
+	server := newTestServer(t, config)
+	service := &blockingService{entered: make(chan struct{}), release: make(chan struct{})}
+	stop := startServer(t, server, service.register)
+
+	result := callBlocking(t, net.JoinHostPort(config.Host, config.Port))
+	<-service.entered
+
+	assert.NoError(t, stop())
+	assert.Error(t, <-result, "expected the stuck call to be aborted by the forced shutdown")
+}
+
+func TestHealthServiceReportsConfiguredStatus(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	server := newTestServer(t, config)
+	startServer(t, server, nil)
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	client := healthpb.NewHealthClient(conn)
+
+	resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	server.SetServingStatus("orders", healthpb.HealthCheckResponse_NOT_SERVING)
+	resp, err = client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "orders"})
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+}
+
+func TestHealthServiceReportsNotServingOnceShutdownBegins(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	stop := startServer(t, newTestServer(t, config), nil)
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	watchCtx, cancelWatch := context.WithCancel(context.Background())
+	defer cancelWatch()
+	watch, err := healthpb.NewHealthClient(conn).Watch(watchCtx, &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+
+	resp, err := watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	stopped := make(chan error, 1)
+	go func() { stopped <- stop() }()
+
+	resp, err = watch.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, resp.Status)
+
+	// The watch is an in-flight stream; end it so the graceful shutdown can complete.
+	cancelWatch()
+	assert.NoError(t, <-stopped)
+}
+
+func TestKeepaliveParamsUsesDefaultsForUnsetFields(t *testing.T) {
+	params := keepaliveParams(&Config{})
+
+	assert.Equal(t, keepalive.ServerParameters{
+		MaxConnectionIdle: maxConnectionIdle,
+		MaxConnectionAge:  maxConnectionAge,
+		Time:              gRPCTime,
+		Timeout:           gRPCTimeout,
+	}, params)
+}
+
+func TestKeepaliveParamsAppliesConfiguredValues(t *testing.T) {
+	params := keepaliveParams(&Config{
+		MaxConnectionIdle: time.Minute,
+		MaxConnectionAge:  2 * time.Minute,
+		KeepaliveTime:     30 * time.Second,
+		KeepaliveTimeout:  5 * time.Second,
+	})
+
+	assert.Equal(t, keepalive.ServerParameters{
+		MaxConnectionIdle: time.Minute,
+		MaxConnectionAge:  2 * time.Minute,
+		Time:              30 * time.Second,
+		Timeout:           5 * time.Second,
+	}, params)
+}
+
+// callWithLargeMessage sends a request of about size bytes to a method that accepts any request.
+func callWithLargeMessage(t *testing.T, config *Config, size int) error {
+	t.Helper()
+	startServer(t, newTestServer(t, config), func(s *grpc.Server) {
+		s.RegisterService(&grpc.ServiceDesc{
+			ServiceName: "test.Large",
+			HandlerType: (*any)(nil),
+			Methods: []grpc.MethodDesc{{
+				MethodName: "Send",
+				Handler: func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					if err := dec(&healthpb.HealthCheckRequest{}); err != nil {
+						return nil, err
+					}
+					return &healthpb.HealthCheckResponse{}, nil
+				},
+			}},
+		}, struct{}{})
+	})
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	request := &healthpb.HealthCheckRequest{Service: strings.Repeat("x", size)}
+	return conn.Invoke(context.Background(), "/test.Large/Send", request, &healthpb.HealthCheckResponse{})
+}
+
+func TestRunGrpcServerRejectsMessagesAboveDefaultLimit(t *testing.T) {
+	err := callWithLargeMessage(t, &Config{Host: "localhost", Port: freePort(t)}, 5<<20)
+
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestRunGrpcServerAcceptsMessagesWithinConfiguredLimit(t *testing.T) {
+	err := callWithLargeMessage(t, &Config{Host: "localhost", Port: freePort(t), MaxRecvMsgSize: 8 << 20}, 5<<20)
+
+	assert.NoError(t, err)
+}
+
+func TestRunGrpcServerReportsAddressInUse(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+
+	err = NewGrpcServer(mocks.NewILogger(t), &Config{Host: "localhost", Port: port}).RunGrpcServer(context.Background())
+
+	assert.ErrorContains(t, err, "failed to listen on "+net.JoinHostPort("localhost", port))
+}
+
+func TestRunGrpcServerStopsShutdownWatcherWhenServeFails(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	mockLogger := mocks.NewILogger(t)
+	mockLogger.On("Infof", "gRPC server listening on port: %s", config.Port).Once()
+	mockLogger.On("Errorf", "gRPC server serve error: %v", grpc.ErrServerStopped).Once()
+	server := NewGrpcServer(mockLogger, config)
+	// A stopped server makes Serve fail right after the listener has been opened.
+	server.Grpc.Stop()
+
+	// RunGrpcServer waits for its shutdown watcher, so returning while ctx is still live proves the watcher exited.
+	done := make(chan error, 1)
+	go func() { done <- server.RunGrpcServer(context.Background()) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected RunGrpcServer to return once Serve failed")
+	}
+	assert.ErrorIs(t, err, grpc.ErrServerStopped)
+	assert.ErrorContains(t, err, "gRPC server on "+net.JoinHostPort(config.Host, config.Port)+" stopped unexpectedly")
+}
+
+func TestRunGrpcServerRegistersReflectionIndependentlyOfDevelopment(t *testing.T) {
+	enabled, disabled := true, false
+	tests := []struct {
+		name             string
+		development      bool
+		enableReflection *bool
+		registered       bool
+	}{
+		{name: "follows development when unset", development: true, registered: true},
+		{name: "off outside development when unset", development: false, registered: false},
+		{name: "enabled outside development", development: false, enableReflection: &enabled, registered: true},
+		{name: "disabled in development", development: true, enableReflection: &disabled, registered: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{Host: "localhost", Port: freePort(t), Development: tt.development, EnableReflection: tt.enableReflection}
+			startServer(t, newTestServer(t, config), nil)
+
+			conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+			require.NoError(t, err)
+			defer conn.Close()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+			require.NoError(t, err)
+			require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+			}))
+			_, err = stream.Recv()
+
+			if tt.registered {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, codes.Unimplemented, status.Code(err))
+			}
+		})
+	}
+}
+
+func TestRunGrpcServerReturnsWithinShutdownTimeoutDespiteEndlessStream(t *testing.T) {
+	const shutdownTimeout = 200 * time.Millisecond
+	config := &Config{Host: "localhost", Port: freePort(t), ShutdownTimeout: shutdownTimeout}
+	opened := make(chan struct{})
+	stop := startServer(t, newTestServer(t, config), func(s *grpc.Server) {
+		s.RegisterService(&grpc.ServiceDesc{
+			ServiceName: "test.Endless",
+			HandlerType: (*any)(nil),
+			Streams: []grpc.StreamDesc{{
+				StreamName:    "Watch",
+				ServerStreams: true,
+				Handler: func(_ any, stream grpc.ServerStream) error {
+					close(opened)
+					<-stream.Context().Done()
+					return stream.Context().Err()
+				},
+			}},
+		}, struct{}{})
+	})
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+	stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, "/test.Endless/Watch")
+	require.NoError(t, err)
+	require.NoError(t, stream.SendMsg(&healthpb.HealthCheckRequest{}))
+	require.NoError(t, stream.CloseSend())
+	<-opened
+
+	start := time.Now()
+	assert.NoError(t, stop())
+	elapsed := time.Since(start)
+
+	assert.GreaterOrEqual(t, elapsed, shutdownTimeout)
+	assert.Less(t, elapsed, shutdownTimeout+time.Second)
+}
@@ -0,0 +1,56 @@
+package server_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/grpc/server"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// TestServerLifecycle drives the server through its exported API only: start, serve a call, shut down.
+func TestServerLifecycle(t *testing.T) {
+	listener, err := net.Listen("tcp", "localhost:0")
+	require.NoError(t, err)
+	_, port, _ := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, listener.Close())
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "gRPC server listening on port: %s", port).Once()
+	log.On("Infof", "shutting down gRPC server on port: %s", port).Once()
+	log.On("Infof", "gRPC server exited properly").Once()
+	log.On("Infof", mock.Anything, mock.Anything).Maybe()
+
+	config := &server.Config{Host: "localhost", Port: port}
+	srv := server.NewGrpcServer(log, config)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- srv.RunGrpcServer(ctx) }()
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	callCtx, cancelCall := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelCall()
+	resp, err := healthpb.NewHealthClient(conn).Check(callCtx, &healthpb.HealthCheckRequest{}, grpc.WaitForReady(true))
+	require.NoError(t, err)
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the server to stop after the context was cancelled")
+	}
+}
@@ -0,0 +1,52 @@
+package server
+
+import (
+	"context"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryLoggingInterceptor logs the method, status code and duration of every unary call.
+// Server errors are logged at error level together with the error, everything else at info level.
+func UnaryLoggingInterceptor(log logger.ILogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		logCall(log, info.FullMethod, time.Since(start), err)
+		return resp, err
+	}
+}
+
+// StreamLoggingInterceptor logs the method, status code and duration of every stream once it ends.
+// Server errors are logged at error level together with the error, everything else at info level.
+func StreamLoggingInterceptor(log logger.ILogger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		err := handler(srv, ss)
+		logCall(log, info.FullMethod, time.Since(start), err)
+		return err
+	}
+}
+
+func logCall(log logger.ILogger, method string, duration time.Duration, err error) {
+	code := status.Code(err)
+	if isServerError(code) {
+		log.Errorf("%s %s %s: %v", method, code, duration, err)
+		return
+	}
+	log.Infof("%s %s %s", method, code, duration)
+}
+
+// isServerError reports whether code signals a failure on the server side rather than a rejected request.
+func isServerError(code codes.Code) bool {
+	switch code {
+	case codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented, codes.Internal, codes.Unavailable, codes.DataLoss:
+		return true
+	default:
+		return false
+	}
+}
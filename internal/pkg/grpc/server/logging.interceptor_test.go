@@ -0,0 +1,79 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const testMethod = "/test.Service/Method"
+
+func TestUnaryLoggingInterceptorLogsSuccessfulCall(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Infof", "%s %s %s", testMethod, codes.OK, mock.Anything).Once()
+
+	interceptor := UnaryLoggingInterceptor(log)
+	resp, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) { return "resp", nil })
+
+	assert.NoError(t, err)
+	assert.Equal(t, "resp", resp)
+}
+
+func TestUnaryLoggingInterceptorLogsClientErrorAtInfoLevel(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Infof", "%s %s %s", testMethod, codes.NotFound, mock.Anything).Once()
+
+	interceptor := UnaryLoggingInterceptor(log)
+	_, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) { return nil, status.Error(codes.NotFound, "missing") })
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestUnaryLoggingInterceptorLogsServerError(t *testing.T) {
+	log := mocks.NewILogger(t)
+	handlerErr := status.Error(codes.Internal, "boom")
+	log.On("Errorf", "%s %s %s: %v", testMethod, codes.Internal, mock.Anything, handlerErr).Once()
+
+	interceptor := UnaryLoggingInterceptor(log)
+	_, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) { return nil, handlerErr })
+
+	assert.Equal(t, handlerErr, err)
+}
+
+func TestStreamLoggingInterceptorLogsPlainErrorAsUnknown(t *testing.T) {
+	log := mocks.NewILogger(t)
+	handlerErr := errors.New("stream broke")
+	log.On("Errorf", "%s %s %s: %v", testMethod, codes.Unknown, mock.Anything, handlerErr).Once()
+
+	interceptor := StreamLoggingInterceptor(log)
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(srv any, stream grpc.ServerStream) error { return handlerErr })
+
+	assert.Equal(t, handlerErr, err)
+}
+
+func TestNewGrpcServerAttachesLoggingInterceptor(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	log := mocks.NewILogger(t)
+	log.On("Infof", mock.Anything, mock.Anything).Maybe()
+	log.On("Infof", mock.Anything).Maybe()
+	log.On("Infof", "%s %s %s", "/grpc.health.v1.Health/Check", codes.OK, mock.Anything).Once()
+
+	server := NewGrpcServer(log, config, grpc.ChainUnaryInterceptor(UnaryLoggingInterceptor(log)))
+	stop := startServer(t, server, nil)
+
+	assert.NoError(t, checkHealth(t, net.JoinHostPort(config.Host, config.Port), insecure.NewCredentials()))
+	assert.NoError(t, stop())
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodRateLimit holds the token-bucket settings of a single method.
+type MethodRateLimit struct {
+	// RequestsPerSecond is the rate at which the method's bucket refills; zero disables limiting.
+	RequestsPerSecond float64
+	// Burst is the number of calls the method accepts at once with a full bucket;
+	// zero defaults to RequestsPerSecond rounded up.
+	Burst int
+}
+
+// RateLimitConfig holds the per-method rate limits of the server.
+type RateLimitConfig struct {
+	// Default applies to every method not listed in Methods, each method getting its own bucket.
+	Default MethodRateLimit
+	// Methods overrides the limit of individual methods, keyed by full method name such as "/pkg.Service/Method".
+	Methods map[string]MethodRateLimit
+}
+
+// UnaryRateLimitInterceptor limits every method to its configured rate, keeping a token bucket per method.
+// Calls over the limit are rejected with codes.ResourceExhausted.
+func UnaryRateLimitInterceptor(cfg RateLimitConfig) grpc.UnaryServerInterceptor {
+	limiters := &methodLimiters{cfg: cfg, limiters: make(map[string]*rate.Limiter)}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if limiter := limiters.get(info.FullMethod); limiter != nil && !limiter.Allow() {
+			return nil, status.Errorf(codes.ResourceExhausted, "rate limit exceeded for %s", info.FullMethod)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// methodLimiters lazily creates the token bucket of each method.
+type methodLimiters struct {
+	cfg      RateLimitConfig
+	mu       sync.Mutex
+	limiters map[string]*rate.Limiter
+}
+
+// get returns the method's limiter, or nil when the method is not limited.
+func (m *methodLimiters) get(method string) *rate.Limiter {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if limiter, ok := m.limiters[method]; ok {
+		return limiter
+	}
+
+	limit, ok := m.cfg.Methods[method]
+	if !ok {
+		limit = m.cfg.Default
+	}
+	var limiter *rate.Limiter
+	if limit.RequestsPerSecond > 0 {
+		burst := limit.Burst
+		if burst <= 0 {
+			burst = int(math.Ceil(limit.RequestsPerSecond))
+		}
+		limiter = rate.NewLimiter(rate.Limit(limit.RequestsPerSecond), burst)
+	}
+	m.limiters[method] = limiter
+	return limiter
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func callMethod(interceptor grpc.UnaryServerInterceptor, method string) error {
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method},
+		func(context.Context, any) (any, error) { return "ok", nil })
+	return err
+}
+
+func TestUnaryRateLimitInterceptorRejectsCallsOverTheLimit(t *testing.T) {
+	interceptor := UnaryRateLimitInterceptor(RateLimitConfig{
+		Methods: map[string]MethodRateLimit{testMethod: {RequestsPerSecond: 0.001, Burst: 3}},
+	})
+
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, callMethod(interceptor, testMethod), "call %d", i+1)
+	}
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callMethod(interceptor, testMethod)))
+}
+
+func TestUnaryRateLimitInterceptorKeepsABucketPerMethod(t *testing.T) {
+	interceptor := UnaryRateLimitInterceptor(RateLimitConfig{Default: MethodRateLimit{RequestsPerSecond: 0.001, Burst: 1}})
+
+	assert.NoError(t, callMethod(interceptor, "/test.Service/A"))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(callMethod(interceptor, "/test.Service/A")))
+	assert.NoError(t, callMethod(interceptor, "/test.Service/B"))
+}
+
+func TestUnaryRateLimitInterceptorLeavesUnlimitedMethodsAlone(t *testing.T) {
+	interceptor := UnaryRateLimitInterceptor(RateLimitConfig{
+		Methods: map[string]MethodRateLimit{"/test.Service/Limited": {RequestsPerSecond: 0.001, Burst: 1}},
+	})
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, callMethod(interceptor, testMethod))
+	}
+}
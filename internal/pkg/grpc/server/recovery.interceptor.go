@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"runtime/debug"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryRecoveryInterceptor recovers from panics in unary handlers, logs them at error level with the
+// stack trace and returns a codes.Internal error to the client instead of crashing the server.
+func UnaryRecoveryInterceptor(log logger.ILogger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(log, info.FullMethod, r)
+			}
+		}()
+		return handler(ctx, req)
+	}
+}
+
+// StreamRecoveryInterceptor recovers from panics in stream handlers, logs them at error level with the
+// stack trace and returns a codes.Internal error to the client instead of crashing the server.
+func StreamRecoveryInterceptor(log logger.ILogger) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = recoverPanic(log, info.FullMethod, r)
+			}
+		}()
+		return handler(srv, ss)
+	}
+}
+
+// recoverPanic logs the recovered value and returns the error reported to the client,
+// which does not expose the panic value.
+func recoverPanic(log logger.ILogger, method string, r any) error {
+	log.Errorf("panic recovered in %s: %v\n%s", method, r, debug.Stack())
+	return status.Error(codes.Internal, "internal server error")
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryRecoveryInterceptorConvertsPanicToInternal(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Errorf", "panic recovered in %s: %v\n%s", testMethod, "boom", mock.Anything).Once()
+
+	interceptor := UnaryRecoveryInterceptor(log)
+	_, err := interceptor(context.Background(), "req", &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) { panic("boom") })
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.NotContains(t, err.Error(), "boom")
+}
+
+func TestStreamRecoveryInterceptorConvertsPanicToInternal(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("Errorf", "panic recovered in %s: %v\n%s", testMethod, "boom", mock.Anything).Once()
+
+	interceptor := StreamRecoveryInterceptor(log)
+	err := interceptor(nil, nil, &grpc.StreamServerInfo{FullMethod: testMethod},
+		func(srv any, stream grpc.ServerStream) error { panic("boom") })
+
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestNewGrpcServerRecoversFromHandlerPanic(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t)}
+	server := newTestServer(t, config)
+	server.Log.(*mocks.ILogger).On("Errorf", "panic recovered in %s: %v\n%s", "/test.Panic/Call", "boom", mock.Anything).Twice()
+
+	startServer(t, server, func(s *grpc.Server) {
+		s.RegisterService(&grpc.ServiceDesc{
+			ServiceName: "test.Panic",
+			HandlerType: (*any)(nil),
+			Methods: []grpc.MethodDesc{{
+				MethodName: "Call",
+				Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+					info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Panic/Call"}
+					return interceptor(ctx, nil, info, func(context.Context, any) (any, error) { panic("boom") })
+				},
+			}},
+		}, struct{}{})
+	})
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	for range 2 {
+		err = conn.Invoke(context.Background(), "/test.Panic/Call", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	}
+}
@@ -0,0 +1,75 @@
+package server
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryTimeoutInterceptorAppliesDefaultTimeout(t *testing.T) {
+	var deadline time.Time
+	_, err := UnaryTimeoutInterceptor(50*time.Millisecond)(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) {
+			deadline, _ = ctx.Deadline()
+			time.Sleep(100 * time.Millisecond)
+			return "late", nil
+		})
+
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+	assert.False(t, deadline.IsZero())
+}
+
+func TestUnaryTimeoutInterceptorKeepsClientDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	clientDeadline, _ := ctx.Deadline()
+
+	resp, err := UnaryTimeoutInterceptor(time.Millisecond)(ctx, nil, &grpc.UnaryServerInfo{FullMethod: testMethod},
+		func(ctx context.Context, req any) (any, error) {
+			deadline, _ := ctx.Deadline()
+			assert.Equal(t, clientDeadline, deadline)
+			return "ok", nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+}
+
+func TestNewGrpcServerAppliesCallTimeout(t *testing.T) {
+	config := &Config{Host: "localhost", Port: freePort(t), CallTimeout: 50 * time.Millisecond}
+	startServer(t, newTestServer(t, config), func(s *grpc.Server) {
+		s.RegisterService(&grpc.ServiceDesc{
+			ServiceName: "test.Slow",
+			HandlerType: (*any)(nil),
+			Methods: []grpc.MethodDesc{{
+				MethodName: "Call",
+				Handler: func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+					if err := dec(&healthpb.HealthCheckRequest{}); err != nil {
+						return nil, err
+					}
+					info := &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.Slow/Call"}
+					return interceptor(ctx, nil, info, func(context.Context, any) (any, error) {
+						time.Sleep(200 * time.Millisecond)
+						return &healthpb.HealthCheckResponse{}, nil
+					})
+				},
+			}},
+		}, struct{}{})
+	})
+
+	conn, err := grpc.NewClient(net.JoinHostPort(config.Host, config.Port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	err = conn.Invoke(context.Background(), "/test.Slow/Call", &healthpb.HealthCheckRequest{}, &healthpb.HealthCheckResponse{})
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err))
+}
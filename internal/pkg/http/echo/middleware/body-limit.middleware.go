@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// BodyLimitMiddleware rejects requests whose body exceeds maxBytes with 413 Request Entity Too Large.
+// Bodies of unknown length are cut off at maxBytes while the handler reads them.
+func BodyLimitMiddleware(maxBytes int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.ContentLength > maxBytes {
+				return echo.ErrStatusRequestEntityTooLarge
+			}
+			req.Body = http.MaxBytesReader(c.Response(), req.Body, maxBytes)
+
+			err := next(c)
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return echo.ErrStatusRequestEntityTooLarge.WithInternal(err)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBodyEchoServer(maxBytes int64) *echo.Echo {
+	e := echo.New()
+	e.Use(BodyLimitMiddleware(maxBytes))
+	e.POST("/items", func(c echo.Context) error {
+		body, err := io.ReadAll(c.Request().Body)
+		if err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, string(body))
+	})
+	return e
+}
+
+func TestBodyLimitMiddlewareRejectsOversizedBody(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newBodyEchoServer(8).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("way too large")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBodyLimitMiddlewareRejectsOversizedBodyOfUnknownLength(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("way too large"))
+	req.ContentLength = -1
+
+	rec := httptest.NewRecorder()
+	newBodyEchoServer(8).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestBodyLimitMiddlewarePassesSmallBodyThrough(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newBodyEchoServer(8).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/items", strings.NewReader("small")))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "small", rec.Body.String())
+}
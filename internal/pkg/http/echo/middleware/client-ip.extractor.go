@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// ClientIPExtractor returns an echo.IPExtractor that resolves the client IP from the X-Forwarded-For
+// and X-Real-IP headers only when the request comes from one of the trusted proxy CIDRs.
+// Requests from any other address are attributed to the socket address, so clients cannot spoof their IP.
+// Assign the result to Echo's IPExtractor for c.RealIP() to use it.
+func ClientIPExtractor(trustedProxies []string) (echo.IPExtractor, error) {
+	trusted := make([]*net.IPNet, 0, len(trustedProxies))
+	for _, cidr := range trustedProxies {
+		_, ipNet, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid trusted proxy CIDR %q", cidr)
+		}
+		trusted = append(trusted, ipNet)
+	}
+
+	isTrusted := func(ip net.IP) bool {
+		for _, ipNet := range trusted {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	return func(req *http.Request) string {
+		remoteIP := socketIP(req)
+		if ip := net.ParseIP(remoteIP); ip == nil || !isTrusted(ip) {
+			return remoteIP
+		}
+
+		if forwarded := req.Header.Get(echo.HeaderXForwardedFor); forwarded != "" {
+			return forwardedClientIP(strings.Split(forwarded, ","), isTrusted, remoteIP)
+		}
+		if realIP := strings.TrimSpace(req.Header.Get(echo.HeaderXRealIP)); net.ParseIP(realIP) != nil {
+			return realIP
+		}
+		return remoteIP
+	}, nil
+}
+
+// forwardedClientIP walks the X-Forwarded-For chain from the nearest hop and returns the first address
+// that is not a trusted proxy. When every hop is trusted, the furthest one is returned.
+func forwardedClientIP(hops []string, isTrusted func(net.IP) bool, fallback string) string {
+	client := fallback
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		ip := net.ParseIP(hop)
+		if ip == nil {
+			return client
+		}
+		client = hop
+		if !isTrusted(ip) {
+			return client
+		}
+	}
+	return client
+}
+
+// socketIP returns the IP of the peer the request was received from.
+func socketIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func resolveClientIP(t *testing.T, remoteAddr string, headers map[string]string) string {
+	t.Helper()
+
+	extractor, err := ClientIPExtractor([]string{"10.0.0.0/8"})
+	if err != nil {
+		t.Fatalf("expected no error building the extractor, got %v", err)
+	}
+
+	e := echo.New()
+	e.IPExtractor = extractor
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = remoteAddr
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	return e.NewContext(req, httptest.NewRecorder()).RealIP()
+}
+
+func TestClientIPExtractorUsesForwardedForFromTrustedProxy(t *testing.T) {
+	ip := resolveClientIP(t, "10.0.0.5:443", map[string]string{echo.HeaderXForwardedFor: "198.51.100.7, 10.0.0.9"})
+
+	assert.Equal(t, "198.51.100.7", ip)
+}
+
+func TestClientIPExtractorSkipsSpoofedHopsBeforeTheFirstUntrustedOne(t *testing.T) {
+	ip := resolveClientIP(t, "10.0.0.5:443", map[string]string{echo.HeaderXForwardedFor: "1.1.1.1, 198.51.100.7"})
+
+	assert.Equal(t, "198.51.100.7", ip)
+}
+
+func TestClientIPExtractorUsesRealIPFromTrustedProxy(t *testing.T) {
+	ip := resolveClientIP(t, "10.0.0.5:443", map[string]string{echo.HeaderXRealIP: "198.51.100.7"})
+
+	assert.Equal(t, "198.51.100.7", ip)
+}
+
+func TestClientIPExtractorIgnoresHeadersFromUntrustedSource(t *testing.T) {
+	ip := resolveClientIP(t, "203.0.113.4:50000", map[string]string{
+		echo.HeaderXForwardedFor: "198.51.100.7",
+		echo.HeaderXRealIP:       "198.51.100.8",
+	})
+
+	assert.Equal(t, "203.0.113.4", ip)
+}
+
+func TestClientIPExtractorRejectsInvalidCIDR(t *testing.T) {
+	_, err := ClientIPExtractor([]string{"not-a-cidr"})
+
+	assert.Error(t, err)
+}
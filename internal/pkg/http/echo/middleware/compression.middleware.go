@@ -0,0 +1,18 @@
+package middleware
+
+import (
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+// DefaultCompressionMinLength is the smallest response body, in bytes, that is compressed when no minimum is configured.
+const DefaultCompressionMinLength = 1024
+
+// CompressionMiddleware gzip-encodes responses for clients that accept it.
+// Bodies shorter than minLength bytes are sent uncompressed; a non-positive minLength uses DefaultCompressionMinLength.
+func CompressionMiddleware(minLength int) echo.MiddlewareFunc {
+	if minLength <= 0 {
+		minLength = DefaultCompressionMinLength
+	}
+	return echomiddleware.GzipWithConfig(echomiddleware.GzipConfig{MinLength: minLength})
+}
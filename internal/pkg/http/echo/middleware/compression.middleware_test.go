@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionMiddlewareCompressesOnlyLargeBodies(t *testing.T) {
+	large := strings.Repeat("a", 2048)
+
+	e := echo.New()
+	e.Use(CompressionMiddleware(1024))
+	e.GET("/large", func(c echo.Context) error {
+		return c.String(http.StatusOK, large)
+	})
+	e.GET("/tiny", func(c echo.Context) error {
+		return c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+	reader, err := gzip.NewReader(rec.Body)
+	if assert.NoError(t, err) {
+		body, err := io.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, large, string(body))
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/tiny", nil)
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+	assert.Equal(t, "ok", rec.Body.String())
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ConcurrencyLimitMiddleware serves at most maxConcurrent requests at a time. Requests arriving while every
+// slot is taken are rejected immediately with 503 Service Unavailable instead of queueing.
+func ConcurrencyLimitMiddleware(maxConcurrent int) echo.MiddlewareFunc {
+	slots := make(chan struct{}, maxConcurrent)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			select {
+			case slots <- struct{}{}:
+				defer func() { <-slots }()
+				return next(c)
+			default:
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "too many concurrent requests")
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConcurrencyLimitMiddlewareRejectsOverflowRequests(t *testing.T) {
+	const slots = 2
+
+	started, release := make(chan struct{}, slots), make(chan struct{})
+	e := echo.New()
+	e.Use(ConcurrencyLimitMiddleware(slots))
+	e.GET("/slow", func(c echo.Context) error {
+		started <- struct{}{}
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	var wg sync.WaitGroup
+	inFlight := make([]*httptest.ResponseRecorder, slots)
+	for i := range inFlight {
+		inFlight[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(rec *httptest.ResponseRecorder) {
+			defer wg.Done()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+		}(inFlight[i])
+	}
+	for i := 0; i < slots; i++ {
+		<-started
+	}
+
+	overflow := httptest.NewRecorder()
+	e.ServeHTTP(overflow, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, overflow.Code)
+
+	close(release)
+	wg.Wait()
+	for _, rec := range inFlight {
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	next := httptest.NewRecorder()
+	e.ServeHTTP(next, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.Equal(t, http.StatusOK, next.Code, "expected slots to be released after requests complete")
+}
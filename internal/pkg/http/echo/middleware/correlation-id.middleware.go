@@ -3,10 +3,15 @@ package middleware
 import (
 	"context"
 
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 )
 
+// correlationIDKey is the context key under which the correlation ID is stored.
+// Being an unexported type, it cannot collide with keys set by other packages.
+type correlationIDKey struct{}
+
 // CorrelationIdMiddleware adds a correlation ID to each HTTP request.
 // If a correlation ID is already present in the request header, it will be used.
 // Otherwise, a new UUID will be generated. The correlation ID will be added
@@ -19,12 +24,28 @@ func CorrelationIdMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
 		id := getCorrelationID(req.Header.Get(headerXCorrelationID))
 
 		c.Response().Header().Set(headerXCorrelationID, id)
-		c.SetRequest(req.WithContext(context.WithValue(req.Context(), headerXCorrelationID, id)))
+		c.SetRequest(req.WithContext(ContextWithCorrelationID(req.Context(), id)))
 
 		return next(c)
 	}
 }
 
+// correlationIDLogField is the log field under which loggers obtained with ILogger.WithContext report the correlation ID.
+const correlationIDLogField = "correlation_id"
+
+// ContextWithCorrelationID returns a copy of ctx carrying the given correlation ID,
+// also as a log field picked up by ILogger.WithContext.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	ctx = logger.ContextWithFields(ctx, map[string]any{correlationIDLogField: id})
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// CorrelationIDFromContext returns the correlation ID stored in ctx and whether one was present.
+func CorrelationIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(correlationIDKey{}).(string)
+	return id, ok && id != ""
+}
+
 // getCorrelationID returns the correlation ID from the header or generates a new one.
 func getCorrelationID(headerID string) string {
 	if headerID == "" {
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func captureCorrelationID(t *testing.T, headerID string) (string, bool, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var (
+		id    string
+		found bool
+	)
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware)
+	e.GET("/items", func(c echo.Context) error {
+		id, found = CorrelationIDFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	if headerID != "" {
+		req.Header.Set(echo.HeaderXCorrelationID, headerID)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return id, found, rec
+}
+
+func TestCorrelationIDFromContextReturnsHeaderID(t *testing.T) {
+	id, found, rec := captureCorrelationID(t, "abc-123")
+
+	assert.True(t, found)
+	assert.Equal(t, "abc-123", id)
+	assert.Equal(t, "abc-123", rec.Header().Get(echo.HeaderXCorrelationID))
+}
+
+func TestCorrelationIDFromContextReturnsGeneratedID(t *testing.T) {
+	id, found, rec := captureCorrelationID(t, "")
+
+	assert.True(t, found)
+	assert.NotEmpty(t, id)
+	assert.Equal(t, id, rec.Header().Get(echo.HeaderXCorrelationID))
+}
+
+func TestCorrelationIDFromContextIgnoresPlainStringKey(t *testing.T) {
+	// A plain string key set by other code must not be mistaken for the correlation ID.
+	ctx := context.WithValue(context.Background(), echo.HeaderXCorrelationID, "foreign")
+
+	_, found := CorrelationIDFromContext(ctx)
+	assert.False(t, found)
+
+	id, found := CorrelationIDFromContext(ContextWithCorrelationID(ctx, "abc-123"))
+	assert.True(t, found)
+	assert.Equal(t, "abc-123", id)
+}
+
+func TestCorrelationIdMiddlewareExposesIDsAsLogFields(t *testing.T) {
+	var fields map[string]any
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware, RequestIdMiddleware)
+	e.GET("/items", func(c echo.Context) error {
+		fields = logger.FieldsFromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(echo.HeaderXCorrelationID, "abc-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, "abc-123", fields["correlation_id"])
+	assert.Equal(t, rec.Header().Get(echo.HeaderXRequestID), fields["request_id"])
+}
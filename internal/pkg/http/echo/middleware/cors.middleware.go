@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+var (
+	// DefaultCORSAllowedOrigins allows every origin when none are configured.
+	DefaultCORSAllowedOrigins = []string{"*"}
+
+	// DefaultCORSAllowedMethods are the methods allowed when none are configured.
+	DefaultCORSAllowedMethods = []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete,
+	}
+
+	// DefaultCORSAllowedHeaders are the request headers allowed when none are configured.
+	DefaultCORSAllowedHeaders = []string{
+		echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, echo.HeaderXCorrelationID,
+	}
+)
+
+// CORSConfig holds the cross-origin resource sharing settings.
+type CORSConfig struct {
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+}
+
+// CORSMiddleware answers preflight requests and sets the Access-Control headers according to cfg.
+// Empty lists fall back to DefaultCORSAllowedOrigins, DefaultCORSAllowedMethods and DefaultCORSAllowedHeaders.
+func CORSMiddleware(cfg CORSConfig) echo.MiddlewareFunc {
+	return echomiddleware.CORSWithConfig(echomiddleware.CORSConfig{
+		AllowOrigins:     orDefault(cfg.AllowedOrigins, DefaultCORSAllowedOrigins),
+		AllowMethods:     orDefault(cfg.AllowedMethods, DefaultCORSAllowedMethods),
+		AllowHeaders:     orDefault(cfg.AllowedHeaders, DefaultCORSAllowedHeaders),
+		AllowCredentials: cfg.AllowCredentials,
+	})
+}
+
+// orDefault returns values, or defaults when values is empty.
+func orDefault(values, defaults []string) []string {
+	if len(values) == 0 {
+		return defaults
+	}
+	return values
+}
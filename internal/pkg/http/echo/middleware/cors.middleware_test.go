@@ -0,0 +1,61 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func preflight(e *echo.Echo, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodOptions, "/items", nil)
+	req.Header.Set(echo.HeaderOrigin, origin)
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodPost)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestCORSMiddlewareAnswersPreflightWithConfiguredHeaders(t *testing.T) {
+	e := echo.New()
+	e.Use(CORSMiddleware(CORSConfig{
+		AllowedOrigins:   []string{"https://app.example.com"},
+		AllowedMethods:   []string{http.MethodGet, http.MethodPost},
+		AllowedHeaders:   []string{echo.HeaderContentType, "X-Custom"},
+		AllowCredentials: true,
+	}))
+
+	rec := preflight(e, "https://app.example.com")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "GET,POST", rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+	assert.Equal(t, "Content-Type,X-Custom", rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+	assert.Equal(t, "true", rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+}
+
+func TestCORSMiddlewareRejectsUnknownOrigin(t *testing.T) {
+	e := echo.New()
+	e.Use(CORSMiddleware(CORSConfig{AllowedOrigins: []string{"https://app.example.com"}}))
+
+	rec := preflight(e, "https://evil.example.com")
+
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
+
+func TestCORSMiddlewareAppliesDefaults(t *testing.T) {
+	e := echo.New()
+	e.Use(CORSMiddleware(CORSConfig{}))
+
+	rec := preflight(e, "https://any.example.com")
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "*", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, strings.Join(DefaultCORSAllowedMethods, ","), rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+	assert.Equal(t, strings.Join(DefaultCORSAllowedHeaders, ","), rec.Header().Get(echo.HeaderAccessControlAllowHeaders))
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowCredentials))
+}
@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+const (
+	headerETag        = "ETag"
+	headerIfNoneMatch = "If-None-Match"
+)
+
+// ETagMiddleware adds a content-hash ETag to successful GET and HEAD responses and answers
+// requests whose If-None-Match matches it with 304 Not Modified. Handlers may set their own ETag,
+// which is then used as is. Responses are buffered in memory, so it is meant for cacheable, bounded payloads.
+func ETagMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				return next(c)
+			}
+
+			res := c.Response()
+			original := res.Writer
+			buffer := &bufferedResponseWriter{ResponseWriter: original, status: http.StatusOK}
+			res.Writer = buffer
+
+			err := next(c)
+			res.Writer = original
+			if !res.Committed {
+				return err
+			}
+
+			if err == nil && buffer.status == http.StatusOK {
+				etag := original.Header().Get(headerETag)
+				if etag == "" {
+					etag = contentETag(buffer.body.Bytes())
+					original.Header().Set(headerETag, etag)
+				}
+				if etagMatches(req.Header.Get(headerIfNoneMatch), etag) {
+					original.Header().Del(echo.HeaderContentLength)
+					original.WriteHeader(http.StatusNotModified)
+					res.Status = http.StatusNotModified
+					return nil
+				}
+			}
+
+			original.WriteHeader(buffer.status)
+			if _, writeErr := original.Write(buffer.body.Bytes()); writeErr != nil && err == nil {
+				return errors.Wrap(writeErr, "failed to write buffered response")
+			}
+			return err
+		}
+	}
+}
+
+// bufferedResponseWriter holds the status and body written by a handler until the ETag is known.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status code.
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// Write buffers the body.
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// Flush is a no-op, as the body is only sent once the handler has returned.
+func (w *bufferedResponseWriter) Flush() {}
+
+// contentETag returns a strong ETag derived from the SHA-256 hash of body.
+func contentETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatches reports whether the If-None-Match header value matches etag, using weak comparison.
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
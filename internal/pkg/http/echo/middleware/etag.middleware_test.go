@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newETagEchoServer() *echo.Echo {
+	e := echo.New()
+	e.Use(ETagMiddleware())
+	e.GET("/items", func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"name": "widget"})
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return c.JSON(http.StatusNotFound, map[string]string{"message": "not found"})
+	})
+	return e
+}
+
+func getWithETag(e *echo.Echo, target, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set(headerIfNoneMatch, ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestETagMiddlewareReturnsETagThenNotModified(t *testing.T) {
+	e := newETagEchoServer()
+
+	first := getWithETag(e, "/items", "")
+	etag := first.Header().Get(headerETag)
+
+	assert.Equal(t, http.StatusOK, first.Code)
+	assert.NotEmpty(t, etag)
+	assert.JSONEq(t, `{"name":"widget"}`, first.Body.String())
+
+	second := getWithETag(e, "/items", etag)
+
+	assert.Equal(t, http.StatusNotModified, second.Code)
+	assert.Equal(t, etag, second.Header().Get(headerETag))
+	assert.Empty(t, second.Body.String())
+}
+
+func TestETagMiddlewareServesBodyForStaleETag(t *testing.T) {
+	rec := getWithETag(newETagEchoServer(), "/items", `"stale"`)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"name":"widget"}`, rec.Body.String())
+}
+
+func TestETagMiddlewareSkipsNonSuccessfulResponses(t *testing.T) {
+	rec := getWithETag(newETagEchoServer(), "/missing", "")
+
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Empty(t, rec.Header().Get(headerETag))
+	assert.JSONEq(t, `{"message":"not found"}`, rec.Body.String())
+}
+
+func TestEtagMatches(t *testing.T) {
+	assert.True(t, etagMatches(`"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`"xyz", W/"abc"`, `"abc"`))
+	assert.True(t, etagMatches(`*`, `"abc"`))
+	assert.False(t, etagMatches(`"xyz"`, `"abc"`))
+	assert.False(t, etagMatches(``, `"abc"`))
+}
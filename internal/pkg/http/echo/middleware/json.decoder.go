@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// DecodeJSONOptions controls how DecodeJSON reads the request body.
+type DecodeJSONOptions struct {
+	// DisallowUnknownFields rejects bodies containing fields that dst does not declare.
+	DisallowUnknownFields bool
+	// MaxBytes rejects bodies larger than this many bytes with 413; zero means no limit.
+	MaxBytes int64
+}
+
+// DecodeJSON decodes the request body as a single JSON value into dst. Unlike c.Bind, decoding failures
+// result in a 400 HTTP error whose body names the offending field, such as a field of the wrong type
+// or, with DisallowUnknownFields, a field dst does not declare.
+func DecodeJSON(c echo.Context, dst interface{}, opts DecodeJSONOptions) error {
+	body := c.Request().Body
+	if opts.MaxBytes > 0 {
+		body = http.MaxBytesReader(c.Response(), body, opts.MaxBytes)
+	}
+
+	decoder := json.NewDecoder(body)
+	if opts.DisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+
+	if err := decoder.Decode(dst); err != nil {
+		return decodeError(err)
+	}
+	if decoder.More() {
+		return invalidJSON(FieldError{Rule: "single", Message: "request body must contain a single JSON value"}, nil)
+	}
+	return nil
+}
+
+// decodeError converts a json.Decoder error into an HTTP error describing what was wrong with the body.
+func decodeError(err error) error {
+	var (
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		maxBytesErr *http.MaxBytesError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return echo.ErrStatusRequestEntityTooLarge.WithInternal(err)
+	case errors.Is(err, io.EOF):
+		return invalidJSON(FieldError{Rule: "required", Message: "request body must not be empty"}, err)
+	case errors.As(err, &syntaxErr):
+		return invalidJSON(FieldError{Rule: "syntax", Message: fmt.Sprintf("malformed JSON at offset %d", syntaxErr.Offset)}, err)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return invalidJSON(FieldError{Rule: "syntax", Message: "malformed JSON: unexpected end of body"}, err)
+	case errors.As(err, &typeErr):
+		return invalidJSON(FieldError{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be of type %s, got %s", typeErr.Field, typeErr.Type, typeErr.Value),
+		}, err)
+	case strings.HasPrefix(err.Error(), "json: unknown field "):
+		field := strings.Trim(strings.TrimPrefix(err.Error(), "json: unknown field "), `"`)
+		return invalidJSON(FieldError{Field: field, Rule: "unknown", Message: field + " is not a known field"}, err)
+	}
+	return invalidJSON(FieldError{Rule: "decode", Message: err.Error()}, err)
+}
+
+// invalidJSON returns a 400 HTTP error carrying the given field error.
+func invalidJSON(fieldErr FieldError, err error) error {
+	response := ValidationErrorResponse{Message: "invalid JSON body", Errors: []FieldError{fieldErr}}
+	return echo.NewHTTPError(http.StatusBadRequest, response).SetInternal(err)
+}
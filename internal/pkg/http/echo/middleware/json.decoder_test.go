@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type updateItemRequest struct {
+	Name     string `json:"name"`
+	Quantity int    `json:"quantity"`
+}
+
+func decodeBody(t *testing.T, body string, opts DecodeJSONOptions) (updateItemRequest, *httptest.ResponseRecorder) {
+	t.Helper()
+
+	var decoded updateItemRequest
+	e := echo.New()
+	e.PUT("/items", func(c echo.Context) error {
+		if err := DecodeJSON(c, &decoded, opts); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/items", strings.NewReader(body)))
+	return decoded, rec
+}
+
+func decodeErrorResponse(t *testing.T, rec *httptest.ResponseRecorder) ValidationErrorResponse {
+	t.Helper()
+
+	var response ValidationErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("expected a JSON error body, got %q: %v", rec.Body.String(), err)
+	}
+	return response
+}
+
+func TestDecodeJSONDecodesValidBody(t *testing.T) {
+	decoded, rec := decodeBody(t, `{"name":"widget","quantity":3}`, DecodeJSONOptions{DisallowUnknownFields: true})
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, updateItemRequest{Name: "widget", Quantity: 3}, decoded)
+}
+
+func TestDecodeJSONRejectsUnknownFields(t *testing.T) {
+	_, rec := decodeBody(t, `{"name":"widget","colour":"red"}`, DecodeJSONOptions{DisallowUnknownFields: true})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []FieldError{{Field: "colour", Rule: "unknown", Message: "colour is not a known field"}},
+		decodeErrorResponse(t, rec).Errors)
+}
+
+func TestDecodeJSONAllowsUnknownFieldsByDefault(t *testing.T) {
+	_, rec := decodeBody(t, `{"name":"widget","colour":"red"}`, DecodeJSONOptions{})
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+}
+
+func TestDecodeJSONNamesFieldWithWrongType(t *testing.T) {
+	_, rec := decodeBody(t, `{"name":"widget","quantity":"three"}`, DecodeJSONOptions{})
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, []FieldError{{Field: "quantity", Rule: "type", Message: "quantity must be of type int, got string"}},
+		decodeErrorResponse(t, rec).Errors)
+}
+
+func TestDecodeJSONReportsMalformedAndOversizedBodies(t *testing.T) {
+	_, rec := decodeBody(t, `{"name":}`, DecodeJSONOptions{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, "syntax", decodeErrorResponse(t, rec).Errors[0].Rule)
+
+	_, rec = decodeBody(t, `{"name":"widget"} {"name":"gadget"}`, DecodeJSONOptions{})
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = decodeBody(t, `{"name":"a very long widget name"}`, DecodeJSONOptions{MaxBytes: 8})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// loggerKey is the context key under which the request-scoped logger is stored.
+type loggerKey struct{}
+
+// LoggerMiddleware stores a child of base with the request's correlation ID as a field in the request context,
+// so handlers can retrieve it with LoggerFromContext. It must run after CorrelationIdMiddleware.
+func LoggerMiddleware(base logger.ILogger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			requestLogger := base
+			if id, ok := CorrelationIDFromContext(req.Context()); ok {
+				requestLogger = base.WithField(correlationIDLogField, id)
+			}
+
+			c.SetRequest(req.WithContext(ContextWithLogger(req.Context(), requestLogger)))
+			return next(c)
+		}
+	}
+}
+
+// ContextWithLogger returns a copy of ctx carrying the given logger.
+func ContextWithLogger(ctx context.Context, l logger.ILogger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, l)
+}
+
+// LoggerFromContext returns the request-scoped logger stored in ctx, falling back to the package-level logger.Logger.
+func LoggerFromContext(ctx context.Context) logger.ILogger {
+	if l, ok := ctx.Value(loggerKey{}).(logger.ILogger); ok {
+		return l
+	}
+	return logger.Logger
+}
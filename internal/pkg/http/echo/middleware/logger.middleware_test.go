@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerMiddlewareAddsCorrelationIDField(t *testing.T) {
+	child := mocks.NewILogger(t)
+	child.On("Infof", "loading item %d", 42).Return().Once()
+	child.On("Error", "lookup failed").Return().Once()
+	base := mocks.NewILogger(t)
+	base.On("WithField", "correlation_id", "abc-123").Return(child).Once()
+
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware, LoggerMiddleware(base))
+	e.GET("/items", func(c echo.Context) error {
+		log := LoggerFromContext(c.Request().Context())
+		log.Infof("loading item %d", 42)
+		log.Error("lookup failed")
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.Header.Set(echo.HeaderXCorrelationID, "abc-123")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLoggerFromContextFallsBackToDefaultLogger(t *testing.T) {
+	previous := logger.Logger
+	defer func() { logger.Logger = previous }()
+
+	fallback := mocks.NewILogger(t)
+	logger.Logger = fallback
+
+	assert.Same(t, fallback, LoggerFromContext(context.Background()))
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func newMetricsEchoServer(t *testing.T) (*echo.Echo, *Metrics, *prometheus.Registry) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics(registry)
+	if err != nil {
+		t.Fatalf("expected no error registering metrics, got %v", err)
+	}
+
+	e := echo.New()
+	e.Use(metrics.Middleware())
+	e.GET("/users/:id", func(c echo.Context) error {
+		return c.String(http.StatusOK, c.Param("id"))
+	})
+	e.GET("/missing", func(c echo.Context) error {
+		return echo.ErrNotFound
+	})
+	e.GET("/metrics", MetricsHandler(registry))
+	return e, metrics, registry
+}
+
+func TestMetricsMiddlewareCountsRequestsByRoute(t *testing.T) {
+	e, metrics, _ := newMetricsEchoServer(t)
+
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodGet, "/users/:id", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(metrics.requests.WithLabelValues(http.MethodGet, "/missing", "404")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(metrics.inFlight.WithLabelValues(http.MethodGet, "/users/:id")))
+	assert.Equal(t, 2, testutil.CollectAndCount(metrics.durations))
+}
+
+func TestMetricsHandlerExposesCollectedMetrics(t *testing.T) {
+	e, _, _ := newMetricsEchoServer(t)
+	e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `http_requests_total{method="GET",route="/users/:id",status="200"} 1`)
+	assert.Contains(t, rec.Body.String(), "http_request_duration_seconds_bucket")
+	assert.Contains(t, rec.Body.String(), "http_requests_in_flight")
+}
+
+func TestNewMetricsFailsWhenRegisteredTwice(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	_, err := NewMetrics(registry)
+	assert.NoError(t, err)
+
+	_, err = NewMetrics(registry)
+	assert.Error(t, err)
+}
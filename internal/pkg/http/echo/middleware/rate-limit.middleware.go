@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+)
+
+// rateLimitVisitorTTL is how long an idle client's bucket is kept before it is discarded.
+const rateLimitVisitorTTL = 3 * time.Minute
+
+// RateLimitConfig holds the token-bucket settings applied to every client.
+type RateLimitConfig struct {
+	// RequestsPerSecond is the rate at which a client's bucket refills.
+	RequestsPerSecond float64
+	// Burst is the number of requests a client can make at once with a full bucket.
+	Burst int
+}
+
+// visitor is the token bucket of a single client.
+type visitor struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// rateLimiter tracks a token bucket per client key.
+type rateLimiter struct {
+	cfg         RateLimitConfig
+	now         func() time.Time
+	mu          sync.Mutex
+	visitors    map[string]*visitor
+	lastCleanup time.Time
+}
+
+// RateLimitMiddleware limits every client to cfg.RequestsPerSecond with bursts of up to cfg.Burst requests.
+// Clients are identified by the subject of the JWT set by ValidateBearerToken when present, and by their IP otherwise.
+// Requests over the limit are rejected with 429 Too Many Requests and a Retry-After header.
+func RateLimitMiddleware(cfg RateLimitConfig) echo.MiddlewareFunc {
+	return newRateLimiter(cfg, time.Now).middleware
+}
+
+// newRateLimiter creates a rate limiter that reads the current time from now.
+func newRateLimiter(cfg RateLimitConfig, now func() time.Time) *rateLimiter {
+	return &rateLimiter{cfg: cfg, now: now, visitors: make(map[string]*visitor), lastCleanup: now()}
+}
+
+// middleware rejects the request when the client's bucket is empty.
+func (l *rateLimiter) middleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		now := l.now()
+		reservation := l.limiter(rateLimitKey(c), now).ReserveN(now, 1)
+		if !reservation.OK() {
+			return echo.ErrTooManyRequests
+		}
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(delay.Seconds()))))
+			return echo.NewHTTPError(http.StatusTooManyRequests, "rate limit exceeded")
+		}
+		return next(c)
+	}
+}
+
+// limiter returns the bucket of the client identified by key, discarding buckets idle for longer than rateLimitVisitorTTL.
+func (l *rateLimiter) limiter(key string, now time.Time) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastCleanup) > rateLimitVisitorTTL {
+		for k, v := range l.visitors {
+			if now.Sub(v.lastSeen) > rateLimitVisitorTTL {
+				delete(l.visitors, k)
+			}
+		}
+		l.lastCleanup = now
+	}
+
+	v, ok := l.visitors[key]
+	if !ok {
+		v = &visitor{limiter: rate.NewLimiter(rate.Limit(l.cfg.RequestsPerSecond), l.cfg.Burst)}
+		l.visitors[key] = v
+	}
+	v.lastSeen = now
+	return v.limiter
+}
+
+// rateLimitKey identifies the client by its token subject when authenticated, or by its IP address.
+func rateLimitKey(c echo.Context) string {
+	if token, ok := c.Get("token").(*jwt.Token); ok {
+		if subject := tokenSubject(token); subject != "" {
+			return "sub:" + subject
+		}
+	}
+	return "ip:" + c.RealIP()
+}
+
+// tokenSubject returns the "sub" claim of the token, if any.
+func tokenSubject(token *jwt.Token) string {
+	switch claims := token.Claims.(type) {
+	case *generates.JWTAccessClaims:
+		return claims.Subject
+	case jwt.MapClaims:
+		subject, _ := claims["sub"].(string)
+		return subject
+	}
+	return ""
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-oauth2/oauth2/v4/generates"
+	"github.com/golang-jwt/jwt"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeClock is a manually advanced time source.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func newRateLimitedEchoServer(clock *fakeClock, cfg RateLimitConfig) *echo.Echo {
+	e := echo.New()
+	e.Use(newRateLimiter(cfg, clock.Now).middleware)
+	e.GET("/items", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	return e
+}
+
+func getFrom(e *echo.Echo, ip string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRateLimitMiddlewareRejectsRequestsOverTheBurst(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	e := newRateLimitedEchoServer(clock, RateLimitConfig{RequestsPerSecond: 0.5, Burst: 2})
+
+	assert.Equal(t, http.StatusOK, getFrom(e, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusOK, getFrom(e, "10.0.0.1").Code)
+
+	rec := getFrom(e, "10.0.0.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get(echo.HeaderRetryAfter))
+
+	assert.Equal(t, http.StatusOK, getFrom(e, "10.0.0.2").Code, "expected other clients to have their own bucket")
+}
+
+func TestRateLimitMiddlewareAllowsRequestsAfterTheWindowResets(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	e := newRateLimitedEchoServer(clock, RateLimitConfig{RequestsPerSecond: 1, Burst: 1})
+
+	assert.Equal(t, http.StatusOK, getFrom(e, "10.0.0.1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, getFrom(e, "10.0.0.1").Code)
+
+	clock.now = clock.now.Add(time.Second)
+	assert.Equal(t, http.StatusOK, getFrom(e, "10.0.0.1").Code)
+}
+
+func TestRateLimitKeyPrefersTokenSubject(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/items", nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	c := e.NewContext(req, httptest.NewRecorder())
+
+	assert.Equal(t, "ip:10.0.0.1", rateLimitKey(c))
+
+	claims := &generates.JWTAccessClaims{StandardClaims: jwt.StandardClaims{Subject: "user-42"}}
+	c.Set("token", &jwt.Token{Claims: claims})
+	assert.Equal(t, "sub:user-42", rateLimitKey(c))
+}
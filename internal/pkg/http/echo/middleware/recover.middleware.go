@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// RecoverMiddleware recovers from panics in downstream handlers, logs them at error level with the log fields
+// of the request context, such as its correlation ID, and responds with a 500 JSON body. The stack trace is included in the
+// response only when debugErrorResponse is true; it is always logged.
+func RecoverMiddleware(log logger.ILogger, debugErrorResponse bool) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			defer func() {
+				r := recover()
+				if r == nil {
+					return
+				}
+				if r == http.ErrAbortHandler {
+					panic(r)
+				}
+
+				stack := debug.Stack()
+				ctx := c.Request().Context()
+				log.WithContext(ctx).Errorf("panic recovered: %v\n%s", r, stack)
+
+				correlationID, _ := CorrelationIDFromContext(ctx)
+
+				err = c.JSON(http.StatusInternalServerError, newPanicResponse(r, correlationID, stack, debugErrorResponse))
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// panicResponse is the JSON body returned for a recovered panic.
+type panicResponse struct {
+	Message       string `json:"message"`
+	CorrelationID string `json:"correlationId,omitempty"`
+	Error         string `json:"error,omitempty"`
+	Stack         string `json:"stack,omitempty"`
+}
+
+// newPanicResponse builds the response body, exposing the panic value and stack only in debug mode.
+func newPanicResponse(r interface{}, correlationID string, stack []byte, debugErrorResponse bool) panicResponse {
+	response := panicResponse{
+		Message:       http.StatusText(http.StatusInternalServerError),
+		CorrelationID: correlationID,
+	}
+	if debugErrorResponse {
+		response.Error = fmt.Sprint(r)
+		response.Stack = string(stack)
+	}
+	return response
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPanickingServer(log *mocks.ILogger, debugErrorResponse bool) *echo.Echo {
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware, RecoverMiddleware(log, debugErrorResponse))
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+	return e
+}
+
+func TestRecoverMiddlewareLogsPanicAndReturns500(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("WithContext", mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.FieldsFromContext(ctx)["correlation_id"] == "abc-123"
+	})).Return(log)
+	log.On("Errorf", "panic recovered: %v\n%s", "boom", mock.Anything).Return()
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(echo.HeaderXCorrelationID, "abc-123")
+	rec := httptest.NewRecorder()
+	newPanickingServer(log, false).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var body panicResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "Internal Server Error", body.Message)
+	assert.Equal(t, "abc-123", body.CorrelationID)
+	assert.Empty(t, body.Error)
+	assert.Empty(t, body.Stack)
+}
+
+func TestRecoverMiddlewareIncludesStackInDebugMode(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("WithContext", mock.Anything).Return(log)
+	log.On("Errorf", "panic recovered: %v\n%s", "boom", mock.Anything).Return()
+
+	rec := httptest.NewRecorder()
+	newPanickingServer(log, true).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	var body panicResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "boom", body.Error)
+	assert.Contains(t, body.Stack, "goroutine")
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// requestIDKey is the context key under which the request ID is stored.
+type requestIDKey struct{}
+
+// RequestIdMiddleware assigns every request a fresh, server-generated ID. Unlike the correlation ID,
+// it is never taken from the client, so it identifies a single request even when clients reuse
+// correlation IDs. The ID is returned in the X-Request-ID response header and stored in the request context.
+func RequestIdMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		req := c.Request()
+		id := uuid.New().String()
+
+		c.Response().Header().Set(echo.HeaderXRequestID, id)
+		c.SetRequest(req.WithContext(ContextWithRequestID(req.Context(), id)))
+
+		return next(c)
+	}
+}
+
+// requestIDLogField is the log field under which loggers obtained with ILogger.WithContext report the request ID.
+const requestIDLogField = "request_id"
+
+// ContextWithRequestID returns a copy of ctx carrying the given request ID,
+// also as a log field picked up by ILogger.WithContext.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	ctx = logger.ContextWithFields(ctx, map[string]any{requestIDLogField: id})
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID stored in ctx and whether one was present.
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestIdMiddlewareGeneratesFreshIDPerRequest(t *testing.T) {
+	var contextIDs []string
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware, RequestIdMiddleware)
+	e.GET("/items", func(c echo.Context) error {
+		id, ok := RequestIDFromContext(c.Request().Context())
+		assert.True(t, ok)
+		contextIDs = append(contextIDs, id)
+		return c.NoContent(http.StatusOK)
+	})
+
+	var responses []*httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/items", nil)
+		req.Header.Set(echo.HeaderXCorrelationID, "shared-correlation-id")
+		req.Header.Set(echo.HeaderXRequestID, "client-chosen-id")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		responses = append(responses, rec)
+	}
+
+	first, second := responses[0].Header().Get(echo.HeaderXRequestID), responses[1].Header().Get(echo.HeaderXRequestID)
+	assert.NotEmpty(t, first)
+	assert.NotEqual(t, first, second)
+	assert.NotEqual(t, "client-chosen-id", first)
+	assert.Equal(t, []string{first, second}, contextIDs)
+
+	for _, rec := range responses {
+		assert.Equal(t, "shared-correlation-id", rec.Header().Get(echo.HeaderXCorrelationID))
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/labstack/echo/v4"
+)
+
+// RequestLoggerMiddleware logs the method, URI, status and latency of every request with the log fields
+// of the request context, such as its correlation ID.
+// Server errors are logged at error level, everything else at info level. Requests whose path is listed
+// in ignoreURLs, such as health checks, are not logged.
+func RequestLoggerMiddleware(log logger.ILogger, ignoreURLs []string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if slices.Contains(ignoreURLs, req.URL.Path) {
+				return next(c)
+			}
+
+			start := time.Now()
+			err := next(c)
+			latency := time.Since(start)
+
+			status := responseStatus(c, err)
+			requestLog := log.WithContext(c.Request().Context())
+			switch {
+			case status >= http.StatusInternalServerError && err != nil:
+				requestLog.Errorf("%s %s %d %s: %v", req.Method, req.RequestURI, status, latency, err)
+			case status >= http.StatusInternalServerError:
+				requestLog.Errorf("%s %s %d %s", req.Method, req.RequestURI, status, latency)
+			default:
+				requestLog.Infof("%s %s %d %s", req.Method, req.RequestURI, status, latency)
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+)
+
+func newLoggedEchoServer(log *mocks.ILogger) *echo.Echo {
+	e := echo.New()
+	e.Use(CorrelationIdMiddleware, RequestLoggerMiddleware(log, []string{"/health"}))
+	e.GET("/items", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	e.GET("/broken", func(c echo.Context) error {
+		return errors.New("database unavailable")
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	return e
+}
+
+func serveWithCorrelationID(e *echo.Echo, target string) {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	req.Header.Set(echo.HeaderXCorrelationID, "abc-123")
+	e.ServeHTTP(httptest.NewRecorder(), req)
+}
+
+// expectCorrelatedLogger expects log to be scoped to a request context carrying the correlation ID abc-123.
+func expectCorrelatedLogger(log *mocks.ILogger) {
+	log.On("WithContext", mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.FieldsFromContext(ctx)[correlationIDLogField] == "abc-123"
+	})).Return(log).Once()
+}
+
+func TestRequestLoggerMiddlewareLogsRequests(t *testing.T) {
+	log := mocks.NewILogger(t)
+	expectCorrelatedLogger(log)
+	log.On("Infof", "%s %s %d %s", http.MethodGet, "/items", http.StatusOK, mock.Anything).Return().Once()
+
+	serveWithCorrelationID(newLoggedEchoServer(log), "/items")
+}
+
+func TestRequestLoggerMiddlewareLogsServerErrorsAtErrorLevel(t *testing.T) {
+	log := mocks.NewILogger(t)
+	expectCorrelatedLogger(log)
+	log.On("Errorf", "%s %s %d %s: %v",
+		http.MethodGet, "/broken", http.StatusInternalServerError, mock.Anything, mock.Anything).Return().Once()
+
+	serveWithCorrelationID(newLoggedEchoServer(log), "/broken")
+}
+
+func TestRequestLoggerMiddlewareSkipsIgnoredURLs(t *testing.T) {
+	log := mocks.NewILogger(t)
+
+	serveWithCorrelationID(newLoggedEchoServer(log), "/health")
+}
@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// TimeoutMiddleware bounds the request context by timeout so that handlers and the calls they make observe
+// the cancellation through c.Request().Context(). When the deadline passes before a response is written,
+// the request fails with 503 Service Unavailable. Keep timeout below the server's WriteTimeout, otherwise
+// the connection is closed before the 503 can be written.
+func TimeoutMiddleware(timeout time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			ctx, cancel := context.WithTimeout(c.Request().Context(), timeout)
+			defer cancel()
+
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Response().Committed {
+				return echo.NewHTTPError(http.StatusServiceUnavailable, "request timed out").SetInternal(err)
+			}
+			return err
+		}
+	}
+}
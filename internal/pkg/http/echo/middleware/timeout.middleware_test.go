@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeoutMiddlewareReturns503WhenHandlerExceedsDeadline(t *testing.T) {
+	e := echo.New()
+	e.Use(TimeoutMiddleware(50 * time.Millisecond))
+	e.GET("/slow", func(c echo.Context) error {
+		select {
+		case <-time.After(time.Second):
+			return c.String(http.StatusOK, "done")
+		case <-c.Request().Context().Done():
+			return c.Request().Context().Err()
+		}
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestTimeoutMiddlewarePassesFastRequestsThrough(t *testing.T) {
+	e := echo.New()
+	e.Use(TimeoutMiddleware(time.Second))
+	e.GET("/fast", func(c echo.Context) error {
+		_, hasDeadline := c.Request().Context().Deadline()
+		assert.True(t, hasDeadline)
+		return c.String(http.StatusOK, "done")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "done", rec.Body.String())
+}
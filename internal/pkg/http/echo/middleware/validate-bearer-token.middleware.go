@@ -15,7 +15,7 @@ import (
 func ValidateBearerToken() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			if isTestEnvironment() {
+			if IsTestEnvironment() {
 				return next(c)
 			}
 
@@ -24,7 +24,7 @@ func ValidateBearerToken() echo.MiddlewareFunc {
 				return echo.NewHTTPError(http.StatusUnauthorized, "missing or invalid bearer token")
 			}
 
-			token, err := parseJWT(authToken)
+			token, err := ParseJWT(authToken)
 			if err != nil {
 				return echo.NewHTTPError(http.StatusUnauthorized, err.Error())
 			}
@@ -35,22 +35,22 @@ func ValidateBearerToken() echo.MiddlewareFunc {
 	}
 }
 
-// isTestEnvironment checks if the application is running in a test environment.
-func isTestEnvironment() bool {
+// IsTestEnvironment checks if the application is running in a test environment.
+func IsTestEnvironment() bool {
 	return os.Getenv("APP_ENV") == "tests"
 }
 
 // extractBearerToken retrieves the Bearer token from the request header or form data.
 func extractBearerToken(r *http.Request) (string, bool) {
 	auth := r.Header.Get("Authorization")
-	if token := extractTokenFromHeader(auth); token != "" {
+	if token := ExtractTokenFromHeader(auth); token != "" {
 		return token, true
 	}
 	return r.FormValue("access_token"), r.FormValue("access_token") != ""
 }
 
-// extractTokenFromHeader extracts the token from the "Authorization" header.
-func extractTokenFromHeader(auth string) string {
+// ExtractTokenFromHeader extracts the token from the value of an "Authorization" header.
+func ExtractTokenFromHeader(auth string) string {
 	const prefix = "Bearer "
 	if auth == "" || !strings.HasPrefix(auth, prefix) {
 		return ""
@@ -58,8 +58,8 @@ func extractTokenFromHeader(auth string) string {
 	return auth[len(prefix):]
 }
 
-// parseJWT parses and validates the JWT token.
-func parseJWT(authToken string) (*jwt.Token, error) {
+// ParseJWT parses and validates the JWT token.
+func ParseJWT(authToken string) (*jwt.Token, error) {
 	return jwt.ParseWithClaims(
 		authToken,
 		&generates.JWTAccessClaims{},
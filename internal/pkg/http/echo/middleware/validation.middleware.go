@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// validate checks bound request bodies against their `validate` struct tags, naming fields after their JSON keys.
+var validate = newValidate()
+
+// FieldError describes a single field that failed validation.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationErrorResponse is the 400 response body returned when a request fails validation.
+type ValidationErrorResponse struct {
+	Message string       `json:"message"`
+	Errors  []FieldError `json:"errors"`
+}
+
+// RequestValidator implements echo.Validator using the `validate` struct tags, so c.Validate can be used directly.
+type RequestValidator struct{}
+
+// Validate runs the `validate` tag rules against i, returning a 400 HTTP error listing the failing fields.
+func (RequestValidator) Validate(i interface{}) error {
+	return validateStruct(i)
+}
+
+// BindAndValidate binds the request into dst and validates it against its `validate` struct tags.
+// Invalid payloads result in a 400 HTTP error whose body lists every failing field.
+func BindAndValidate(c echo.Context, dst interface{}) error {
+	if err := c.Bind(dst); err != nil {
+		return err
+	}
+	return validateStruct(dst)
+}
+
+// validateStruct validates i and converts validation failures into a 400 HTTP error.
+func validateStruct(i interface{}) error {
+	err := validate.Struct(i)
+	if err == nil {
+		return nil
+	}
+
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	response := ValidationErrorResponse{Message: "validation failed", Errors: make([]FieldError, 0, len(validationErrs))}
+	for _, fieldErr := range validationErrs {
+		response.Errors = append(response.Errors, FieldError{
+			Field:   fieldErr.Field(),
+			Rule:    fieldErr.Tag(),
+			Message: fieldErrorMessage(fieldErr),
+		})
+	}
+	return echo.NewHTTPError(http.StatusBadRequest, response).SetInternal(err)
+}
+
+// fieldErrorMessage returns a human-readable description of a failed rule.
+func fieldErrorMessage(fieldErr validator.FieldError) string {
+	if fieldErr.Param() == "" {
+		return fieldErr.Field() + " failed the " + fieldErr.Tag() + " rule"
+	}
+	return fieldErr.Field() + " failed the " + fieldErr.Tag() + "=" + fieldErr.Param() + " rule"
+}
+
+// newValidate creates a validator that reports fields by their JSON names.
+func newValidate() *validator.Validate {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return field.Name
+		}
+		return name
+	})
+	return v
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type createUserRequest struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"required,email"`
+	Age   int    `json:"age" validate:"gte=18"`
+}
+
+func newValidatingEchoServer() *echo.Echo {
+	e := echo.New()
+	e.POST("/users", func(c echo.Context) error {
+		var req createUserRequest
+		if err := BindAndValidate(c, &req); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, req)
+	})
+	return e
+}
+
+func postJSON(e *echo.Echo, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBindAndValidateAcceptsValidPayload(t *testing.T) {
+	rec := postJSON(newValidatingEchoServer(), `{"name":"Alice","email":"alice@example.com","age":30}`)
+
+	assert.Equal(t, http.StatusCreated, rec.Code)
+}
+
+func TestBindAndValidateReturnsFieldErrors(t *testing.T) {
+	rec := postJSON(newValidatingEchoServer(), `{"email":"not-an-email","age":12}`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	var body ValidationErrorResponse
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "validation failed", body.Message)
+	assert.ElementsMatch(t, []FieldError{
+		{Field: "name", Rule: "required", Message: "name failed the required rule"},
+		{Field: "email", Rule: "email", Message: "email failed the email rule"},
+		{Field: "age", Rule: "gte", Message: "age failed the gte=18 rule"},
+	}, body.Errors)
+}
+
+func TestBindAndValidateRejectsMalformedJSON(t *testing.T) {
+	rec := postJSON(newValidatingEchoServer(), `{"name":`)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestRequestValidatorPlugsIntoEcho(t *testing.T) {
+	e := echo.New()
+	e.Validator = RequestValidator{}
+
+	err := e.Validator.Validate(&createUserRequest{Name: "Alice", Email: "alice@example.com", Age: 30})
+	assert.NoError(t, err)
+
+	err = e.Validator.Validate(&createUserRequest{})
+	var httpErr *echo.HTTPError
+	if assert.ErrorAs(t, err, &httpErr) {
+		assert.Equal(t, http.StatusBadRequest, httpErr.Code)
+	}
+}
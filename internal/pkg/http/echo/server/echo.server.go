@@ -3,29 +3,61 @@ package server
 import (
 	"context"
 	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"net/http/pprof"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/NekKkMirror/go-app/internal/pkg/http/echo/middleware"
 	"github.com/NekKkMirror/go-app/internal/pkg/logger"
 	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
 	"github.com/pkg/errors"
 )
 
 const (
-	MaxHeaderBytes = 1 << 20
-	ReadTimeout    = 15 * time.Second
-	WriteTimeout   = 15 * time.Second
+	MaxHeaderBytes  = 1 << 20
+	ReadTimeout     = 15 * time.Second
+	WriteTimeout    = 15 * time.Second
+	ShutdownTimeout = 10 * time.Second
 )
 
+// versionSegment matches a path segment naming an API version, such as "v1" or "v12".
+var versionSegment = regexp.MustCompile(`^v[0-9]+$`)
+
 // EchoConfig holds the configuration for the Echo server.
 type EchoConfig struct {
-	Host               string   `mapstructure:"host"`
-	Port               string   `mapstructure:"port" validate:"required"`
-	Development        string   `mapstructure:"development"`
-	BasePath           string   `mapstructure:"basePath" validate:"required"`
-	DebugErrorResponse bool     `mapstructure:"debugErrorResponse"`
-	IgnoreLogUrls      []string `mapstructure:"ignoreLogUrls"`
-	Timeout            string   `mapstructure:"timeout"`
+	Host                 string   `mapstructure:"host"`
+	Port                 string   `mapstructure:"port" validate:"required"`
+	Development          string   `mapstructure:"development"`
+	BasePath             string   `mapstructure:"basePath" validate:"required"`
+	DebugErrorResponse   bool     `mapstructure:"debugErrorResponse"`
+	IgnoreLogUrls        []string `mapstructure:"ignoreLogUrls"`
+	Timeout              string   `mapstructure:"timeout"`
+	RequestTimeout       string   `mapstructure:"requestTimeout"`
+	ShutdownTimeout      string   `mapstructure:"shutdownTimeout"`
+	AllowedOrigins       []string `mapstructure:"allowedOrigins"`
+	AllowedMethods       []string `mapstructure:"allowedMethods"`
+	AllowedHeaders       []string `mapstructure:"allowedHeaders"`
+	AllowCredentials     bool     `mapstructure:"allowCredentials"`
+	EnableCompression    bool     `mapstructure:"enableCompression"`
+	CompressionMinLength int      `mapstructure:"compressionMinLength"`
+	MaxBodyBytes         int64    `mapstructure:"maxBodyBytes"`
+	RateLimitPerSecond   float64  `mapstructure:"rateLimitPerSecond"`
+	RateLimitBurst       int      `mapstructure:"rateLimitBurst"`
+	TrustedProxies       []string `mapstructure:"trustedProxies"`
+	MaxConcurrent        int      `mapstructure:"maxConcurrent"`
+}
+
+// IsDevelopment reports whether Development is set to a true value such as "true" or "1".
+func (cfg *EchoConfig) IsDevelopment() bool {
+	development, err := strconv.ParseBool(cfg.Development)
+	return err == nil && development
 }
 
 // NewEchoServer creates and returns a new Echo instance.
@@ -33,57 +65,397 @@ func NewEchoServer() *echo.Echo {
 	return echo.New()
 }
 
-// RunHttpServer runs the HTTP server and handles graceful shutdown on context cancellation.
+// NewConfiguredEchoServer creates a new Echo instance with the default middleware stack configured by cfg.
+func NewConfiguredEchoServer(cfg *EchoConfig, log logger.ILogger) *echo.Echo {
+	e := NewEchoServer()
+	e.Use(
+		middleware.CorrelationIdMiddleware,
+		middleware.RequestIdMiddleware,
+		middleware.LoggerMiddleware(log),
+		middleware.RequestLoggerMiddleware(log, cfg.IgnoreLogUrls),
+		middleware.RecoverMiddleware(log, cfg.DebugErrorResponse),
+	)
+
+	if len(cfg.AllowedOrigins) > 0 {
+		ApplyCORS(e, cfg)
+	}
+	ApplyCompression(e, cfg)
+	return e
+}
+
+// RunHttpServer serves e on Host:Port until ctx is cancelled, then shuts it down gracefully
+// within ShutdownTimeout.
 func RunHttpServer(ctx context.Context, e *echo.Echo, log logger.ILogger, cfg *EchoConfig) error {
-	configureServer(e, cfg)
+	if err := configureServer(e, cfg); err != nil {
+		return err
+	}
+
+	shutdownTimeout, err := parseShutdownTimeout(cfg)
+	if err != nil {
+		return err
+	}
+
+	address := fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.Wrapf(err, "failed to listen on %s", address)
+	}
+	e.Listener = listener
 
+	serverStopped := make(chan struct{})
+	shutdownDone := make(chan struct{})
 	go func() {
-		<-ctx.Done()
-		shutdownServer(e, log, cfg, ctx)
+		defer close(shutdownDone)
+		select {
+		case <-ctx.Done():
+			shutdownServer(e, log, cfg, shutdownTimeout)
+		case <-serverStopped:
+		}
 	}()
 
-	err := e.Start(fmt.Sprintf("%s:%s", cfg.Host, cfg.Port))
+	err = e.Start(address)
+	close(serverStopped)
+	<-shutdownDone
+
 	if err != nil && !errors.Is(err, http.ErrServerClosed) {
-		return err
+		return errors.Wrapf(err, "HTTP server on %s stopped unexpectedly", address)
 	}
-
 	return nil
 }
 
 // configureServer sets the various server options such as timeouts and header sizes.
-func configureServer(e *echo.Echo, cfg *EchoConfig) {
+// A configured Timeout overrides the default read and write timeouts and also bounds idle connections.
+func configureServer(e *echo.Echo, cfg *EchoConfig) error {
 	e.Server.MaxHeaderBytes = MaxHeaderBytes
 	e.Server.ReadTimeout = ReadTimeout
 	e.Server.WriteTimeout = WriteTimeout
+
+	timeout, err := parseTimeout(cfg)
+	if err != nil || timeout == 0 {
+		return err
+	}
+
+	e.Server.ReadTimeout = timeout
+	e.Server.WriteTimeout = timeout
+	e.Server.IdleTimeout = timeout
+	return nil
+}
+
+// parseTimeout returns the configured Timeout, or zero when it is not set.
+func parseTimeout(cfg *EchoConfig) (time.Duration, error) {
+	if cfg.Timeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.Timeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid server timeout %q", cfg.Timeout)
+	}
+	if timeout <= 0 {
+		return 0, errors.Errorf("server timeout must be positive, got %q", cfg.Timeout)
+	}
+	return timeout, nil
+}
+
+// parseRequestTimeout returns the configured RequestTimeout, or zero when it is not set.
+func parseRequestTimeout(cfg *EchoConfig) (time.Duration, error) {
+	if cfg.RequestTimeout == "" {
+		return 0, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.RequestTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid request timeout %q", cfg.RequestTimeout)
+	}
+	if timeout <= 0 {
+		return 0, errors.Errorf("request timeout must be positive, got %q", cfg.RequestTimeout)
+	}
+	return timeout, nil
+}
+
+// parseShutdownTimeout returns the configured shutdown timeout, falling back to ShutdownTimeout when it is empty.
+func parseShutdownTimeout(cfg *EchoConfig) (time.Duration, error) {
+	if cfg.ShutdownTimeout == "" {
+		return ShutdownTimeout, nil
+	}
+
+	timeout, err := time.ParseDuration(cfg.ShutdownTimeout)
+	if err != nil {
+		return 0, errors.Wrapf(err, "invalid shutdown timeout %q", cfg.ShutdownTimeout)
+	}
+	if timeout <= 0 {
+		return 0, errors.Errorf("shutdown timeout must be positive, got %q", cfg.ShutdownTimeout)
+	}
+	return timeout, nil
 }
 
 // shutdownServer gracefully shuts down the Echo server.
-func shutdownServer(e *echo.Echo, log logger.ILogger, cfg *EchoConfig, ctx context.Context) {
+// It stops accepting new requests and waits for active ones to drain, forcing the server closed once timeout elapses.
+// It uses a fresh context because the one that triggered the shutdown is already cancelled.
+func shutdownServer(e *echo.Echo, log logger.ILogger, cfg *EchoConfig, timeout time.Duration) {
 	log.Infof("shutting down HTTP server on port: %s", cfg.Port)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
 	if err := e.Shutdown(ctx); err != nil {
 		log.Errorf("(shutdown) error: %v", err)
+		if closeErr := e.Close(); closeErr != nil {
+			log.Errorf("(shutdown) failed to force close: %v", closeErr)
+		}
 		return
 	}
 	log.Info("server exited properly")
 }
 
+// ApplyConcurrencyLimit caps the number of requests served at once to MaxConcurrent, rejecting the overflow with 503.
+// It does nothing when MaxConcurrent is not positive.
+func ApplyConcurrencyLimit(e *echo.Echo, cfg *EchoConfig) {
+	if cfg.MaxConcurrent > 0 {
+		e.Use(middleware.ConcurrencyLimitMiddleware(cfg.MaxConcurrent))
+	}
+}
+
+// ApplyRateLimit registers a per-client rate limit of RateLimitPerSecond requests with bursts of RateLimitBurst.
+// It does nothing when RateLimitPerSecond is not positive.
+func ApplyRateLimit(e *echo.Echo, cfg *EchoConfig) {
+	if cfg.RateLimitPerSecond <= 0 {
+		return
+	}
+
+	burst := cfg.RateLimitBurst
+	if burst <= 0 {
+		burst = int(math.Ceil(cfg.RateLimitPerSecond))
+	}
+	e.Use(middleware.RateLimitMiddleware(middleware.RateLimitConfig{RequestsPerSecond: cfg.RateLimitPerSecond, Burst: burst}))
+}
+
+// ApplyTrustedProxies makes c.RealIP() honor X-Forwarded-For and X-Real-IP only for requests coming from
+// the TrustedProxies CIDRs; every other request is attributed to its socket address.
+func ApplyTrustedProxies(e *echo.Echo, cfg *EchoConfig) error {
+	extractor, err := middleware.ClientIPExtractor(cfg.TrustedProxies)
+	if err != nil {
+		return err
+	}
+	e.IPExtractor = extractor
+	return nil
+}
+
 // ApplyVersioningFromHeader applies versioning to the Echo instance based on the "version" header.
 func ApplyVersioningFromHeader(e *echo.Echo) {
 	e.Pre(apiVersion)
 }
 
+// ApplyVersioningFromHeaderWithDefault applies versioning based on the "version" header,
+// falling back to defaultVersion when the header is absent.
+func ApplyVersioningFromHeaderWithDefault(e *echo.Echo, defaultVersion string) {
+	e.Pre(versionFromHeader("", defaultVersion))
+}
+
+// ApplyBasePathVersioningFromHeader applies header versioning to routes mounted under cfg.BasePath.
+// Paths are composed as BasePath, then version, then route, so with BasePath "/api" and header "v1"
+// a request for "/api/users" is routed to "/api/v1/users". Requests outside BasePath, and requests
+// whose path already carries a version right after BasePath, are left untouched, so prefixes are never doubled.
+func ApplyBasePathVersioningFromHeader(e *echo.Echo, cfg *EchoConfig, defaultVersion string) {
+	e.Pre(versionFromHeader(cfg.BasePath, defaultVersion))
+}
+
 // apiVersion is a middleware function that prefixes the request path with the version from the "version" header.
 func apiVersion(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		req := c.Request()
-		version := req.Header.Get("version")
-		req.URL.Path = fmt.Sprintf("/%s%s", version, req.URL.Path)
-		return next(c)
+	return versionFromHeader("", "")(next)
+}
+
+// versionFromHeader returns a middleware that inserts the version from the "version" header, or defaultVersion
+// when the header is absent, right after basePath in the request path. The path is left untouched when neither
+// is set, when it lies outside basePath or when it already carries a version.
+func versionFromHeader(basePath, defaultVersion string) echo.MiddlewareFunc {
+	base := strings.TrimRight(path.Join("/", basePath), "/")
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			version := strings.Trim(req.Header.Get("version"), "/")
+			if version == "" {
+				version = strings.Trim(defaultVersion, "/")
+			}
+			if version != "" {
+				req.URL.Path = insertVersion(base, version, req.URL.Path)
+				if req.URL.RawPath != "" {
+					req.URL.RawPath = insertVersion(base, version, req.URL.RawPath)
+				}
+			}
+			return next(c)
+		}
+	}
+}
+
+// insertVersion inserts the version right after base in urlPath, unless urlPath lies outside base
+// or already carries a version there.
+func insertVersion(base, version, urlPath string) string {
+	rest, ok := cutBasePath(base, urlPath)
+	if !ok || hasVersionPrefix(rest) {
+		return urlPath
+	}
+	return base + versionedPath(version, rest)
+}
+
+// cutBasePath returns urlPath relative to base and whether urlPath lies under base. An empty base matches every path.
+func cutBasePath(base, urlPath string) (string, bool) {
+	switch {
+	case base == "":
+		return urlPath, true
+	case urlPath == base:
+		return "/", true
+	}
+	rest, ok := strings.CutPrefix(urlPath, base+"/")
+	return "/" + rest, ok
+}
+
+// versionedPath prefixes urlPath with the version and collapses repeated slashes.
+func versionedPath(version, urlPath string) string {
+	joined := "/" + version + "/" + urlPath
+	for strings.Contains(joined, "//") {
+		joined = strings.ReplaceAll(joined, "//", "/")
+	}
+	return joined
+}
+
+// ApplyCORS registers the CORS middleware on the Echo instance using the Allowed* settings of cfg.
+func ApplyCORS(e *echo.Echo, cfg *EchoConfig) {
+	e.Use(middleware.CORSMiddleware(middleware.CORSConfig{
+		AllowedOrigins:   cfg.AllowedOrigins,
+		AllowedMethods:   cfg.AllowedMethods,
+		AllowedHeaders:   cfg.AllowedHeaders,
+		AllowCredentials: cfg.AllowCredentials,
+	}))
+}
+
+// ApplyRequestTimeout registers a middleware that bounds every request's context by RequestTimeout and responds
+// with 503 once it passes. RequestTimeout must be below the server's write timeout, Timeout or WriteTimeout when
+// unset, so that the 503 can still be written. It does nothing when no RequestTimeout is configured.
+func ApplyRequestTimeout(e *echo.Echo, cfg *EchoConfig) error {
+	requestTimeout, err := parseRequestTimeout(cfg)
+	if err != nil || requestTimeout == 0 {
+		return err
+	}
+
+	writeTimeout, err := parseTimeout(cfg)
+	if err != nil {
+		return err
+	}
+	if writeTimeout == 0 {
+		writeTimeout = WriteTimeout
+	}
+	if requestTimeout >= writeTimeout {
+		return errors.Errorf("request timeout %s must be below the server write timeout %s", requestTimeout, writeTimeout)
+	}
+
+	e.Use(middleware.TimeoutMiddleware(requestTimeout))
+	return nil
+}
+
+// ApplyCompression registers gzip compression for responses of at least CompressionMinLength bytes
+// when EnableCompression is set.
+func ApplyCompression(e *echo.Echo, cfg *EchoConfig) {
+	if cfg.EnableCompression {
+		e.Use(middleware.CompressionMiddleware(cfg.CompressionMinLength))
+	}
+}
+
+// ApplyBodyLimit registers a middleware that rejects request bodies larger than MaxBodyBytes.
+// It does nothing when MaxBodyBytes is not positive.
+func ApplyBodyLimit(e *echo.Echo, cfg *EchoConfig) {
+	if cfg.MaxBodyBytes > 0 {
+		e.Use(middleware.BodyLimitMiddleware(cfg.MaxBodyBytes))
 	}
 }
 
+// ApplyVersioningFromPath routes requests whose path does not start with a version segment such as "/v1"
+// to defaultVersion, so "/users" resolves to "/v1/users". Paths that already carry a version are left untouched.
+func ApplyVersioningFromPath(e *echo.Echo, defaultVersion string) {
+	defaultVersion = strings.Trim(defaultVersion, "/")
+	e.Pre(func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			if defaultVersion != "" && !hasVersionPrefix(req.URL.Path) {
+				req.URL.Path = versionedPath(defaultVersion, req.URL.Path)
+				if req.URL.RawPath != "" {
+					req.URL.RawPath = versionedPath(defaultVersion, req.URL.RawPath)
+				}
+			}
+			return next(c)
+		}
+	})
+}
+
+// hasVersionPrefix reports whether the first segment of urlPath is a version such as "v1".
+func hasVersionPrefix(urlPath string) bool {
+	segment, _, _ := strings.Cut(strings.TrimLeft(urlPath, "/"), "/")
+	return versionSegment.MatchString(segment)
+}
+
+// RegisterVersionedGroup registers a route group mounted under the given version, e.g. "v1" serves "/v1/...".
+func RegisterVersionedGroup(e *echo.Echo, version string, builder func(g *echo.Group)) *echo.Echo {
+	return RegisterGroupFunc("/"+strings.Trim(version, "/"), e, builder)
+}
+
+// RegisterStatic serves the files in dir under urlPrefix. Request paths are cleaned before being resolved
+// against dir, so they cannot escape it.
+func RegisterStatic(e *echo.Echo, urlPrefix, dir string) *echo.Echo {
+	return registerStatic(e, urlPrefix, dir, false)
+}
+
+// RegisterSPA serves the files in dir under urlPrefix like RegisterStatic, falling back to dir/index.html
+// for paths that match neither a file nor a route so that a single-page application can handle them.
+func RegisterSPA(e *echo.Echo, urlPrefix, dir string) *echo.Echo {
+	return registerStatic(e, urlPrefix, dir, true)
+}
+
+// registerStatic mounts the static file middleware at urlPrefix, optionally with the index.html fallback.
+func registerStatic(e *echo.Echo, urlPrefix, dir string, spaFallback bool) *echo.Echo {
+	static := echomiddleware.StaticWithConfig(echomiddleware.StaticConfig{
+		Root:  dir,
+		HTML5: spaFallback,
+	})
+
+	prefix := path.Join("/", urlPrefix)
+	if prefix == "/" {
+		e.Use(static)
+		return e
+	}
+	e.Group(prefix, static)
+	return e
+}
+
+// RegisterPprof mounts the net/http/pprof profiling endpoints under /debug/pprof when the server runs in development.
+// Nothing is registered otherwise, so profiling is never exposed in production.
+func RegisterPprof(e *echo.Echo, cfg *EchoConfig) *echo.Echo {
+	if !cfg.IsDevelopment() {
+		return e
+	}
+
+	g := e.Group("/debug/pprof")
+	g.GET("/", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	g.GET("/cmdline", echo.WrapHandler(http.HandlerFunc(pprof.Cmdline)))
+	g.GET("/profile", echo.WrapHandler(http.HandlerFunc(pprof.Profile)))
+	g.Match([]string{http.MethodGet, http.MethodPost}, "/symbol", echo.WrapHandler(http.HandlerFunc(pprof.Symbol)))
+	g.GET("/trace", echo.WrapHandler(http.HandlerFunc(pprof.Trace)))
+	g.GET("/:profile", echo.WrapHandler(http.HandlerFunc(pprof.Index)))
+	return e
+}
+
+// RegisterGroup creates a route group with the given name and returns it for further configuration.
+func RegisterGroup(e *echo.Echo, groupName string, m ...echo.MiddlewareFunc) *echo.Group {
+	return e.Group(groupName, m...)
+}
+
 // RegisterGroupFunc registers a route group with the given name and builder function.
 func RegisterGroupFunc(groupName string, e *echo.Echo, builder func(g *echo.Group)) *echo.Echo {
-	builder(e.Group(groupName))
+	builder(RegisterGroup(e, groupName))
 	return e
 }
+
+// RegisterBasePathGroupFunc registers a route group with the given name under the configured BasePath.
+func RegisterBasePathGroupFunc(groupName string, e *echo.Echo, cfg *EchoConfig, builder func(g *echo.Group)) *echo.Echo {
+	return RegisterGroupFunc(path.Join("/", cfg.BasePath, groupName), e, builder)
+}
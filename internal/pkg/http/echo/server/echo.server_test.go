@@ -2,15 +2,22 @@ package server
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
 	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestNewEchoServerInitialization(t *testing.T) {
@@ -54,6 +61,56 @@ func TestApplyVersioningFromHeaderMiddleware(t *testing.T) {
 	}
 }
 
+func TestApiVersionPrefixesPathFromHeader(t *testing.T) {
+	tests := []struct {
+		name           string
+		header         string
+		defaultVersion string
+		path           string
+		expected       string
+	}{
+		{name: "present", header: "v1", path: "/tests", expected: "/v1/tests"},
+		{name: "absent", path: "/tests", expected: "/tests"},
+		{name: "absent with default", defaultVersion: "v2", path: "/tests", expected: "/v2/tests"},
+		{name: "header overrides default", header: "v1", defaultVersion: "v2", path: "/tests", expected: "/v1/tests"},
+		{name: "already slashed", header: "/v1/", path: "/tests", expected: "/v1/tests"},
+		{name: "double slashes", header: "v1", path: "//tests//items", expected: "/v1/tests/items"},
+		{name: "already versioned", header: "v1", path: "/v2/tests", expected: "/v2/tests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.header != "" {
+				req.Header.Set("version", tt.header)
+			}
+			c := e.NewContext(req, httptest.NewRecorder())
+
+			handler := versionFromHeader("", tt.defaultVersion)(func(c echo.Context) error {
+				return c.NoContent(http.StatusOK)
+			})
+
+			assert.NoError(t, handler(c))
+			assert.Equal(t, tt.expected, req.URL.Path)
+		})
+	}
+}
+
+func TestApplyVersioningFromHeaderWithDefaultRoutesToDefaultVersion(t *testing.T) {
+	e := echo.New()
+	ApplyVersioningFromHeaderWithDefault(e, "v1")
+	e.GET("/v1/tests", func(c echo.Context) error {
+		return c.String(http.StatusOK, "v1")
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tests", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "v1", rec.Body.String())
+}
+
 func TestRegisterGroupWithValidNameAndBuilder(t *testing.T) {
 	e := echo.New()
 	groupName := "/tests"
@@ -76,6 +133,7 @@ func TestRunHttpServerWithTimeoutSettings(t *testing.T) {
 
 	log := &mocks.ILogger{}
 	log.On("Infof", "shutting down HTTP server on port: %s", "8080").Return()
+	log.On("Info", "server exited properly").Return()
 
 	cfg := &EchoConfig{
 		Host:     "localhost",
@@ -104,3 +162,627 @@ func TestRunHttpServerWithTimeoutSettings(t *testing.T) {
 
 	log.AssertExpectations(t)
 }
+
+func TestConfigureServerAppliesParsedTimeout(t *testing.T) {
+	e := echo.New()
+	cfg := &EchoConfig{Port: "8080", BasePath: "/api/v1", Timeout: "30s"}
+
+	err := configureServer(e, cfg)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Second, e.Server.ReadTimeout)
+	assert.Equal(t, 30*time.Second, e.Server.WriteTimeout)
+	assert.Equal(t, 30*time.Second, e.Server.IdleTimeout)
+	assert.Equal(t, MaxHeaderBytes, e.Server.MaxHeaderBytes)
+}
+
+func TestConfigureServerRejectsInvalidTimeout(t *testing.T) {
+	for _, timeout := range []string{"soon", "-5s"} {
+		e := echo.New()
+		err := configureServer(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", Timeout: timeout})
+		assert.Error(t, err, "expected timeout %q to be rejected", timeout)
+	}
+}
+
+func TestRunHttpServerFailsForInvalidTimeout(t *testing.T) {
+	log := mocks.NewILogger(t)
+	cfg := &EchoConfig{Host: "localhost", Port: "0", BasePath: "/api/v1", Timeout: "soon"}
+
+	err := RunHttpServer(context.Background(), echo.New(), log, cfg)
+
+	assert.Error(t, err)
+}
+
+func TestRegisterBasePathGroupFuncMountsUnderBasePath(t *testing.T) {
+	e := echo.New()
+	cfg := &EchoConfig{Port: "8080", BasePath: "/api/v1"}
+
+	RegisterBasePathGroupFunc("/tests", e, cfg, func(g *echo.Group) {
+		g.GET("/endpoint", func(c echo.Context) error {
+			return c.String(http.StatusOK, "Hello, World!")
+		})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tests/endpoint", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "/api/v1/tests/endpoint", e.Routes()[0].Path)
+}
+
+func TestRunHttpServerWaitsForInFlightRequestsOnShutdown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "shutting down HTTP server on port: %s", "0").Return()
+	log.On("Info", "server exited properly").Return()
+
+	started, finished := make(chan struct{}), make(chan struct{})
+	e := echo.New()
+	e.GET("/slow", func(c echo.Context) error {
+		close(started)
+		defer close(finished)
+		time.Sleep(500 * time.Millisecond)
+		return c.String(http.StatusOK, "done")
+	})
+
+	cfg := &EchoConfig{Host: "localhost", Port: "0", BasePath: "/api/v1", ShutdownTimeout: "5s"}
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- RunHttpServer(ctx, e, log, cfg)
+	}()
+
+	var addr string
+	assert.Eventually(t, func() bool {
+		if listenerAddr := e.ListenerAddr(); listenerAddr != nil {
+			addr = listenerAddr.String()
+			return true
+		}
+		return false
+	}, time.Second, 10*time.Millisecond)
+
+	responses := make(chan *http.Response, 1)
+	go func() {
+		resp, err := http.Get("http://" + addr + "/slow")
+		assert.NoError(t, err)
+		responses <- resp
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-serverDone:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected RunHttpServer to return after shutdown")
+	}
+
+	select {
+	case <-finished:
+	default:
+		t.Fatal("expected the in-flight request to complete before RunHttpServer returned")
+	}
+
+	resp := <-responses
+	if assert.NotNil(t, resp) {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+}
+
+func TestRunHttpServerFailsForInvalidShutdownTimeout(t *testing.T) {
+	log := mocks.NewILogger(t)
+	cfg := &EchoConfig{Host: "localhost", Port: "0", BasePath: "/api/v1", ShutdownTimeout: "0s"}
+
+	err := RunHttpServer(context.Background(), echo.New(), log, cfg)
+
+	assert.Error(t, err)
+}
+
+func TestRunHttpServerBindsToHostAndPort(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "shutting down HTTP server on port: %s", "0").Return()
+	log.On("Info", "server exited properly").Return()
+
+	e := echo.New()
+	cfg := &EchoConfig{Host: "127.0.0.1", Port: "0", BasePath: "/api/v1"}
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- RunHttpServer(ctx, e, log, cfg)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return e.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	addr, ok := e.ListenerAddr().(*net.TCPAddr)
+	if assert.True(t, ok, "expected a TCP listener") {
+		assert.Equal(t, "127.0.0.1", addr.IP.String())
+	}
+
+	cancel()
+	assert.NoError(t, <-serverDone)
+}
+
+func TestApplyCORSUsesConfiguredOrigins(t *testing.T) {
+	e := echo.New()
+	ApplyCORS(e, &EchoConfig{
+		Port:           "8080",
+		BasePath:       "/api/v1",
+		AllowedOrigins: []string{"https://app.example.com"},
+		AllowedMethods: []string{http.MethodGet},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/tests", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, http.MethodGet, rec.Header().Get(echo.HeaderAccessControlAllowMethods))
+}
+
+func TestApplyRequestTimeoutRespondsWith503(t *testing.T) {
+	e := echo.New()
+	assert.NoError(t, ApplyRequestTimeout(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", RequestTimeout: "50ms"}))
+	e.GET("/slow", func(c echo.Context) error {
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
+
+func TestApplyRequestTimeoutRespondsWith503ThroughRunningServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "shutting down HTTP server on port: %s", "0").Return()
+	log.On("Info", "server exited properly").Return()
+
+	cfg := &EchoConfig{Host: "localhost", Port: "0", BasePath: "/api/v1", Timeout: "500ms", RequestTimeout: "100ms"}
+	e := echo.New()
+	assert.NoError(t, ApplyRequestTimeout(e, cfg))
+	e.GET("/slow", func(c echo.Context) error {
+		<-c.Request().Context().Done()
+		return c.Request().Context().Err()
+	})
+
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- RunHttpServer(ctx, e, log, cfg)
+	}()
+	assert.Eventually(t, func() bool {
+		return e.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	resp, err := http.Get("http://" + e.ListenerAddr().String() + "/slow")
+	if assert.NoError(t, err, "expected a response rather than a reset connection") {
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	}
+
+	cancel()
+	assert.NoError(t, <-serverDone)
+}
+
+func TestApplyRequestTimeoutRejectsTimeoutNotBelowWriteTimeout(t *testing.T) {
+	for _, cfg := range []*EchoConfig{
+		{Port: "8080", BasePath: "/api/v1", Timeout: "1s", RequestTimeout: "1s"},
+		{Port: "8080", BasePath: "/api/v1", RequestTimeout: WriteTimeout.String()},
+		{Port: "8080", BasePath: "/api/v1", RequestTimeout: "soon"},
+	} {
+		assert.Error(t, ApplyRequestTimeout(echo.New(), cfg), "expected request timeout %q to be rejected", cfg.RequestTimeout)
+	}
+
+	assert.NoError(t, ApplyRequestTimeout(echo.New(), &EchoConfig{Port: "8080", BasePath: "/api/v1", Timeout: "1s"}))
+}
+
+func TestApplyCompressionOnlyWhenEnabled(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		e := echo.New()
+		ApplyCompression(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", EnableCompression: enabled, CompressionMinLength: 16})
+		e.GET("/tests", func(c echo.Context) error {
+			return c.String(http.StatusOK, strings.Repeat("a", 64))
+		})
+
+		req := httptest.NewRequest(http.MethodGet, "/tests", nil)
+		req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		if enabled {
+			assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+		} else {
+			assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+		}
+	}
+}
+
+func TestApplyBodyLimitRejectsOversizedBody(t *testing.T) {
+	e := echo.New()
+	ApplyBodyLimit(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", MaxBodyBytes: 4})
+	e.POST("/tests", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tests", strings.NewReader("too large")))
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+func TestRegisterVersionedGroupRoutesByPathPrefix(t *testing.T) {
+	e := echo.New()
+	ApplyVersioningFromPath(e, "v1")
+	for _, version := range []string{"v1", "v2"} {
+		RegisterVersionedGroup(e, version, func(g *echo.Group) {
+			g.GET("/users", func(c echo.Context) error {
+				return c.String(http.StatusOK, version)
+			})
+		})
+	}
+
+	tests := map[string]string{
+		"/v1/users": "v1",
+		"/v2/users": "v2",
+		"/users":    "v1",
+	}
+	for path, expected := range tests {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Equal(t, http.StatusOK, rec.Code, "path %s", path)
+		assert.Equal(t, expected, rec.Body.String(), "path %s", path)
+	}
+}
+
+func TestHasVersionPrefix(t *testing.T) {
+	assert.True(t, hasVersionPrefix("/v1/users"))
+	assert.True(t, hasVersionPrefix("/v12"))
+	assert.False(t, hasVersionPrefix("/users"))
+	assert.False(t, hasVersionPrefix("/video/1"))
+	assert.False(t, hasVersionPrefix("/"))
+}
+
+func TestRunHttpServerForcesCloseWhenDrainExceedsShutdownTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "shutting down HTTP server on port: %s", "0").Return()
+	log.On("Errorf", "(shutdown) error: %v", mock.Anything).Return()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	defer close(release)
+	e := echo.New()
+	e.GET("/stuck", func(c echo.Context) error {
+		close(started)
+		<-release
+		return c.NoContent(http.StatusOK)
+	})
+
+	cfg := &EchoConfig{Host: "localhost", Port: "0", BasePath: "/api/v1", ShutdownTimeout: "100ms"}
+	serverDone := make(chan error, 1)
+	go func() {
+		serverDone <- RunHttpServer(ctx, e, log, cfg)
+	}()
+
+	assert.Eventually(t, func() bool {
+		return e.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+
+	go func() {
+		resp, err := http.Get("http://" + e.ListenerAddr().String() + "/stuck")
+		if err == nil {
+			resp.Body.Close()
+		}
+	}()
+
+	<-started
+	cancel()
+
+	select {
+	case err := <-serverDone:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected RunHttpServer to return once the shutdown timeout elapsed")
+	}
+}
+
+// newStaticDir creates a directory with an index page and an asset, next to a file that must stay private.
+func newStaticDir(t *testing.T) string {
+	root := t.TempDir()
+	dir := filepath.Join(root, "public")
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "assets"), 0o755))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>index</html>"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("console.log('app')"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(root, "secret.txt"), []byte("secret"), 0o644))
+	return dir
+}
+
+func serveStatic(e *echo.Echo, target string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.URL.Path = target
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRegisterStaticServesExistingFile(t *testing.T) {
+	e := echo.New()
+	RegisterStatic(e, "/static", newStaticDir(t))
+
+	rec := serveStatic(e, "/static/assets/app.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "console.log('app')", rec.Body.String())
+
+	rec = serveStatic(e, "/static/unknown")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestRegisterStaticPreventsPathTraversal(t *testing.T) {
+	e := echo.New()
+	RegisterStatic(e, "/static", newStaticDir(t))
+
+	for _, target := range []string{"/static/../secret.txt", "/static/%2e%2e/secret.txt", "/static/assets/../../secret.txt"} {
+		rec := serveStatic(e, target)
+		assert.NotContains(t, rec.Body.String(), "secret", "target %s", target)
+	}
+}
+
+func TestRegisterSPAFallsBackToIndexForUnknownPath(t *testing.T) {
+	e := echo.New()
+	RegisterSPA(e, "/app", newStaticDir(t))
+
+	rec := serveStatic(e, "/app/users/42")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "<html>index</html>", rec.Body.String())
+
+	rec = serveStatic(e, "/app/assets/app.js")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "console.log('app')", rec.Body.String())
+}
+
+func TestApplyRateLimitRejectsRequestsOverTheBurst(t *testing.T) {
+	e := echo.New()
+	ApplyRateLimit(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", RateLimitPerSecond: 1, RateLimitBurst: 1})
+	e.GET("/tests", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	codes := make([]int, 0, 2)
+	for i := 0; i < 2; i++ {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/tests", nil))
+		codes = append(codes, rec.Code)
+	}
+
+	assert.Equal(t, []int{http.StatusOK, http.StatusTooManyRequests}, codes)
+}
+
+func TestNewConfiguredEchoServerWiresDefaultMiddleware(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("WithField", "correlation_id", mock.Anything).Return(log)
+	// The recover and request logger middleware both log the panicking request with its correlation ID.
+	log.On("WithContext", mock.MatchedBy(func(ctx context.Context) bool {
+		return logger.FieldsFromContext(ctx)["correlation_id"] == "abc-123"
+	})).Return(log).Twice()
+	log.On("WithContext", mock.Anything).Return(log).Once()
+	log.On("Infof", "%s %s %d %s", http.MethodGet, "/tests", http.StatusOK, mock.Anything).Return().Once()
+	log.On("Errorf", "panic recovered: %v\n%s", "boom", mock.Anything).Return().Once()
+	log.On("Errorf", "%s %s %d %s", http.MethodGet, "/panic", http.StatusInternalServerError, mock.Anything).Return().Once()
+
+	e := NewConfiguredEchoServer(&EchoConfig{
+		Port:              "8080",
+		BasePath:          "/api/v1",
+		IgnoreLogUrls:     []string{"/health"},
+		AllowedOrigins:    []string{"https://app.example.com"},
+		EnableCompression: true,
+	}, log)
+	e.GET("/tests", func(c echo.Context) error {
+		return c.String(http.StatusOK, strings.Repeat("a", 4096))
+	})
+	e.GET("/panic", func(c echo.Context) error {
+		panic("boom")
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tests", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderXCorrelationID))
+	assert.NotEmpty(t, rec.Header().Get(echo.HeaderXRequestID))
+	assert.Equal(t, "https://app.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "gzip", rec.Header().Get(echo.HeaderContentEncoding))
+
+	req = httptest.NewRequest(http.MethodGet, "/panic", nil)
+	req.Header.Set(echo.HeaderXCorrelationID, "abc-123")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "abc-123", rec.Header().Get(echo.HeaderXCorrelationID))
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestNewConfiguredEchoServerSkipsOptionalMiddleware(t *testing.T) {
+	log := mocks.NewILogger(t)
+	log.On("WithField", "correlation_id", mock.Anything).Return(log)
+	log.On("WithContext", mock.Anything).Return(log).Once()
+	log.On("Infof", "%s %s %d %s", http.MethodGet, "/tests", http.StatusOK, mock.Anything).Return().Once()
+
+	e := NewConfiguredEchoServer(&EchoConfig{Port: "8080", BasePath: "/api/v1"}, log)
+	e.GET("/tests", func(c echo.Context) error {
+		return c.String(http.StatusOK, strings.Repeat("a", 4096))
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/tests", nil)
+	req.Header.Set(echo.HeaderOrigin, "https://app.example.com")
+	req.Header.Set(echo.HeaderAcceptEncoding, "gzip")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, rec.Header().Get(echo.HeaderContentEncoding))
+}
+
+func TestRunHttpServerReportsAddressAlreadyInUse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := mocks.NewILogger(t)
+	log.On("Infof", "shutting down HTTP server on port: %s", "0").Return()
+	log.On("Info", "server exited properly").Return()
+
+	first := echo.New()
+	firstDone := make(chan error, 1)
+	go func() {
+		firstDone <- RunHttpServer(ctx, first, log, &EchoConfig{Host: "127.0.0.1", Port: "0", BasePath: "/api/v1"})
+	}()
+
+	assert.Eventually(t, func() bool {
+		return first.ListenerAddr() != nil
+	}, time.Second, 10*time.Millisecond)
+	port := strconv.Itoa(first.ListenerAddr().(*net.TCPAddr).Port)
+
+	secondDone := make(chan error, 1)
+	go func() {
+		secondDone <- RunHttpServer(ctx, echo.New(), mocks.NewILogger(t), &EchoConfig{Host: "127.0.0.1", Port: port, BasePath: "/api/v1"})
+	}()
+
+	select {
+	case err := <-secondDone:
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "failed to listen on 127.0.0.1:"+port)
+			assert.Contains(t, err.Error(), "address already in use")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the second server to fail promptly")
+	}
+
+	cancel()
+	assert.NoError(t, <-firstDone)
+}
+
+func TestRegisterGroupReturnsGroupForChaining(t *testing.T) {
+	e := echo.New()
+	handler := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	g := RegisterGroup(e, "/tests")
+	g.GET("/first", handler)
+	g.Group("/nested").POST("/second", handler)
+
+	paths := make([]string, 0, len(e.Routes()))
+	for _, route := range e.Routes() {
+		paths = append(paths, route.Method+" "+route.Path)
+	}
+	assert.ElementsMatch(t, []string{"GET /tests/first", "POST /tests/nested/second"}, paths)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tests/nested/second", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestRegisterPprofOnlyInDevelopment(t *testing.T) {
+	tests := map[string]int{
+		"true":  http.StatusOK,
+		"1":     http.StatusOK,
+		"false": http.StatusNotFound,
+		"":      http.StatusNotFound,
+	}
+
+	for development, expected := range tests {
+		e := echo.New()
+		RegisterPprof(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", Development: development})
+
+		for _, target := range []string{"/debug/pprof/", "/debug/pprof/goroutine"} {
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+			assert.Equal(t, expected, rec.Code, "development %q, target %s", development, target)
+		}
+	}
+}
+
+func TestApplyTrustedProxiesResolvesClientIP(t *testing.T) {
+	e := echo.New()
+	assert.NoError(t, ApplyTrustedProxies(e, &EchoConfig{Port: "8080", BasePath: "/api/v1", TrustedProxies: []string{"10.0.0.0/8"}}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.RemoteAddr = "10.1.2.3:443"
+	req.Header.Set(echo.HeaderXForwardedFor, "198.51.100.7")
+	assert.Equal(t, "198.51.100.7", e.NewContext(req, httptest.NewRecorder()).RealIP())
+
+	req.RemoteAddr = "203.0.113.4:443"
+	assert.Equal(t, "203.0.113.4", e.NewContext(req, httptest.NewRecorder()).RealIP())
+
+	assert.Error(t, ApplyTrustedProxies(echo.New(), &EchoConfig{TrustedProxies: []string{"10.0.0.0"}}))
+}
+
+func TestApplyBasePathVersioningFromHeaderComposesPrefixes(t *testing.T) {
+	cfg := &EchoConfig{Port: "8080", BasePath: "/api"}
+	e := echo.New()
+	ApplyBasePathVersioningFromHeader(e, cfg, "")
+	RegisterBasePathGroupFunc("/v1", e, cfg, func(g *echo.Group) {
+		g.GET("/users", func(c echo.Context) error {
+			return c.String(http.StatusOK, "v1 users")
+		})
+	})
+	e.GET("/health", func(c echo.Context) error {
+		return c.String(http.StatusOK, "healthy")
+	})
+
+	tests := []struct {
+		path     string
+		version  string
+		expected string
+	}{
+		{path: "/api/users", version: "v1", expected: "v1 users"},
+		{path: "/api/v1/users", version: "v1", expected: "v1 users"},
+		{path: "/api/v1/users", expected: "v1 users"},
+		{path: "/health", version: "v1", expected: "healthy"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.version != "" {
+			req.Header.Set("version", tt.version)
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, "path %s, version %q", tt.path, tt.version)
+		assert.Equal(t, tt.expected, rec.Body.String(), "path %s, version %q", tt.path, tt.version)
+	}
+}
+
+func TestInsertVersion(t *testing.T) {
+	assert.Equal(t, "/api/v1/users", insertVersion("/api", "v1", "/api/users"))
+	assert.Equal(t, "/api/v1/", insertVersion("/api", "v1", "/api"))
+	assert.Equal(t, "/api/v2/users", insertVersion("/api", "v1", "/api/v2/users"))
+	assert.Equal(t, "/apiary/users", insertVersion("/api", "v1", "/apiary/users"))
+	assert.Equal(t, "/v1/users", insertVersion("", "v1", "/users"))
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pkg/errors"
+)
+
+// MIMETextEventStream is the content type of server-sent event streams.
+const MIMETextEventStream = "text/event-stream"
+
+// StreamJSON streams every item received from ch to the client as a JSON-encoded server-sent event,
+// flushing each one as it arrives. It returns nil once ch is closed or the client disconnects.
+func StreamJSON(c echo.Context, ch <-chan any) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMETextEventStream)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case item, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			data, err := json.Marshal(item)
+			if err != nil {
+				return errors.Wrap(err, "failed to encode stream event")
+			}
+			if _, err := fmt.Fprintf(res, "data: %s\n\n", data); err != nil {
+				return errors.Wrap(err, "failed to write stream event")
+			}
+			res.Flush()
+		}
+	}
+}
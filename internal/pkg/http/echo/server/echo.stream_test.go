@@ -0,0 +1,90 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamJSONSendsEventsUntilChannelCloses(t *testing.T) {
+	e := echo.New()
+	e.GET("/progress", func(c echo.Context) error {
+		ch := make(chan any)
+		go func() {
+			defer close(ch)
+			for i := 1; i <= 3; i++ {
+				ch <- map[string]int{"step": i}
+			}
+		}()
+		return StreamJSON(c, ch)
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/progress")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, MIMETextEventStream, resp.Header.Get(echo.HeaderContentType))
+	assert.Equal(t, "no-cache", resp.Header.Get(echo.HeaderCacheControl))
+
+	var events []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if line := scanner.Text(); strings.HasPrefix(line, "data: ") {
+			events = append(events, strings.TrimPrefix(line, "data: "))
+		}
+	}
+
+	assert.NoError(t, scanner.Err())
+	assert.Equal(t, []string{`{"step":1}`, `{"step":2}`, `{"step":3}`}, events)
+}
+
+func TestStreamJSONStopsWhenClientDisconnects(t *testing.T) {
+	streamDone := make(chan error, 1)
+	e := echo.New()
+	e.GET("/progress", func(c echo.Context) error {
+		ch := make(chan any, 1)
+		ch <- "first"
+		err := StreamJSON(c, ch)
+		streamDone <- err
+		return err
+	})
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/progress", nil)
+	if !assert.NoError(t, err) {
+		cancel()
+		return
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		cancel()
+		return
+	}
+	defer resp.Body.Close()
+
+	line, err := bufio.NewReader(resp.Body).ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "data: \"first\"\n", line)
+
+	cancel()
+
+	select {
+	case err := <-streamDone:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the stream to stop after the client disconnected")
+	}
+}
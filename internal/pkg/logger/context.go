@@ -0,0 +1,27 @@
+package logger
+
+import (
+	"context"
+	"maps"
+)
+
+// fieldsKey is the context key under which log fields are stored.
+type fieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying the given log fields in addition to those already in ctx.
+// Loggers obtained with WithContext add them to every entry.
+func ContextWithFields(ctx context.Context, fields map[string]any) context.Context {
+	merged := maps.Clone(FieldsFromContext(ctx))
+	if merged == nil {
+		merged = make(map[string]any, len(fields))
+	}
+	maps.Copy(merged, fields)
+	return context.WithValue(ctx, fieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the log fields stored in ctx, or nil when there are none.
+// The returned map must not be modified.
+func FieldsFromContext(ctx context.Context) map[string]any {
+	fields, _ := ctx.Value(fieldsKey{}).(map[string]any)
+	return fields
+}
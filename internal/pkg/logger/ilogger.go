@@ -1,6 +1,8 @@
 package logger
 
 import (
+	"context"
+
 	log "github.com/sirupsen/logrus"
 )
 
@@ -19,4 +21,13 @@ type ILogger interface {
 	Fatalf(format string, args ...interface{})
 	Trace(args ...interface{})
 	Tracef(format string, args ...interface{})
+	// WithField returns a child logger that adds the given field to every entry it logs.
+	WithField(key string, value any) ILogger
+	// WithFields returns a child logger that adds the given fields to every entry it logs.
+	WithFields(fields map[string]any) ILogger
+	// WithContext returns a child logger that adds the fields stored in ctx, such as the correlation
+	// and request IDs, to every entry it logs.
+	WithContext(ctx context.Context) ILogger
+	// Sync flushes any buffered log entries to the underlying output. Call it before the application exits.
+	Sync() error
 }
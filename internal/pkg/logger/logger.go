@@ -1,11 +1,15 @@
 package logger
 
 import (
+	"context"
+	"fmt"
+	"io"
 	"os"
 
 	"sync"
 
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // Logger holds the singleton instance of the logger
@@ -14,11 +18,28 @@ var once sync.Once
 
 type Config struct {
 	LogLevel string
+	// Format selects the log format, "json" or "text". When empty, JSON is used if APP_ENV is "production".
+	Format string
+	// Output receives the log entries; nil keeps the logger's current output, standard error by default.
+	Output io.Writer
+	// Filename writes the log entries to a file, in addition to Output when both are set. The file is rotated
+	// once it exceeds MaxSizeMB (100 when zero); rotated files are removed when they are older than MaxAgeDays
+	// or exceed MaxBackups, and kept when these are zero.
+	Filename   string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAgeDays int
+	// Sampling limits how often repetitive messages are logged; nil logs every message.
+	Sampling *SamplingConfig
 }
 
 type appLogger struct {
 	level  string
 	logger *log.Logger
+	// entry carries the fields added with WithField and WithFields; every message is logged through it.
+	entry *log.Entry
+	// sampler drops repetitive entries when sampling is configured; it is nil otherwise.
+	sampler *sampler
 }
 
 var loggerLevelMap = map[string]log.Level{
@@ -40,77 +61,180 @@ func (l *appLogger) GetLevel() log.Level {
 	return level
 }
 
-// InitLogger initializes the logger with the given config
+// InitLogger initializes the default logger with the given config, backed by logrus' standard logger.
+// Only the first call configures it; later calls return the existing Logger.
 func InitLogger(cfg *Config) ILogger {
 	once.Do(func() {
-		l := &appLogger{level: cfg.LogLevel}
-		l.logger = log.StandardLogger()
-
-		l.setupFormatter()
-		log.SetLevel(l.GetLevel())
-
-		Logger = l
+		Logger = newConfiguredLogger(cfg, log.StandardLogger())
 	})
 	return Logger
 }
 
-func (l *appLogger) setupFormatter() {
-	env := os.Getenv("APP_ENV")
-	if env == "production" {
-		log.SetFormatter(&log.JSONFormatter{})
-	} else {
-		log.SetFormatter(&log.TextFormatter{
-			DisableColors: false,
-			ForceColors:   true,
-			FullTimestamp: true,
-		})
+// NewLogger creates an independent logger with the given config. Unlike InitLogger it neither touches
+// logrus' standard logger nor the package-level Logger, so several loggers can be configured side by side.
+func NewLogger(cfg *Config) ILogger {
+	return newConfiguredLogger(cfg, log.New())
+}
+
+// newAppLogger creates a logger writing through base.
+func newAppLogger(level string, base *log.Logger) *appLogger {
+	return &appLogger{level: level, logger: base, entry: log.NewEntry(base)}
+}
+
+// newConfiguredLogger creates a logger writing through base and applies the level, format and output of cfg to base.
+func newConfiguredLogger(cfg *Config, base *log.Logger) *appLogger {
+	l := newAppLogger(cfg.LogLevel, base)
+
+	l.setupFormatter(cfg.Format)
+	base.SetLevel(l.GetLevel())
+	if output := cfg.output(); output != nil {
+		base.SetOutput(output)
+	}
+	if cfg.Sampling != nil {
+		l.sampler = newSampler(*cfg.Sampling)
+	}
+	return l
+}
+
+// output returns the writer for the entries configured by Output and Filename, or nil when neither is set.
+func (c *Config) output() io.Writer {
+	if c.Filename == "" {
+		return c.Output
+	}
+
+	file := &lumberjack.Logger{
+		Filename:   c.Filename,
+		MaxSize:    c.MaxSizeMB,
+		MaxBackups: c.MaxBackups,
+		MaxAge:     c.MaxAgeDays,
+	}
+	if c.Output == nil {
+		return file
+	}
+	return teeWriter{c.Output, file}
+}
+
+func (l *appLogger) setupFormatter(format string) {
+	l.logger.SetFormatter(newFormatter(format))
+}
+
+// newFormatter returns the formatter for the given format, falling back to APP_ENV when the format is not set.
+func newFormatter(format string) log.Formatter {
+	if format == "" && os.Getenv("APP_ENV") == "production" {
+		format = "json"
+	}
+	if format == "json" {
+		return &log.JSONFormatter{}
+	}
+	return &log.TextFormatter{
+		DisableColors: false,
+		ForceColors:   true,
+		FullTimestamp: true,
+	}
+}
+
+// sampled reports whether an entry with the given level should be logged. Entries are sampled by their
+// format string, or by their message when logged without one. Entries below the logger's level are
+// discarded by logrus anyway and do not count towards the sample.
+func (l *appLogger) sampled(level log.Level, format string, args []interface{}) bool {
+	if l.sampler == nil || !l.logger.IsLevelEnabled(level) {
+		return true
+	}
+	if format == "" {
+		format = fmt.Sprint(args...)
 	}
+	return l.sampler.allow(level, format)
 }
 
 func (l *appLogger) Debug(args ...interface{}) {
-	l.logger.Debug(args...)
+	if l.sampled(log.DebugLevel, "", args) {
+		l.entry.Debug(args...)
+	}
 }
 
 func (l *appLogger) Debugf(format string, args ...interface{}) {
-	l.logger.Debugf(format, args...)
+	if l.sampled(log.DebugLevel, format, nil) {
+		l.entry.Debugf(format, args...)
+	}
 }
 
 func (l *appLogger) Info(args ...interface{}) {
-	l.logger.Info(args...)
+	if l.sampled(log.InfoLevel, "", args) {
+		l.entry.Info(args...)
+	}
 }
 
 func (l *appLogger) Infof(format string, args ...interface{}) {
-	l.logger.Infof(format, args...)
+	if l.sampled(log.InfoLevel, format, nil) {
+		l.entry.Infof(format, args...)
+	}
 }
 
 func (l *appLogger) Warn(args ...interface{}) {
-	l.logger.Warn(args...)
+	if l.sampled(log.WarnLevel, "", args) {
+		l.entry.Warn(args...)
+	}
 }
 
 func (l *appLogger) Warnf(format string, args ...interface{}) {
-	l.logger.Warnf(format, args...)
+	if l.sampled(log.WarnLevel, format, nil) {
+		l.entry.Warnf(format, args...)
+	}
 }
 
 func (l *appLogger) Error(args ...interface{}) {
-	l.logger.Error(args...)
+	if l.sampled(log.ErrorLevel, "", args) {
+		l.entry.Error(args...)
+	}
 }
 
 func (l *appLogger) Errorf(format string, args ...interface{}) {
-	l.logger.Errorf(format, args...)
+	if l.sampled(log.ErrorLevel, format, nil) {
+		l.entry.Errorf(format, args...)
+	}
 }
 
 func (l *appLogger) Fatal(args ...interface{}) {
-	l.logger.Fatal(args...)
+	l.entry.Fatal(args...)
 }
 
 func (l *appLogger) Fatalf(format string, args ...interface{}) {
-	l.logger.Fatalf(format, args...)
+	l.entry.Fatalf(format, args...)
 }
 
 func (l *appLogger) Trace(args ...interface{}) {
-	l.logger.Trace(args...)
+	if l.sampled(log.TraceLevel, "", args) {
+		l.entry.Trace(args...)
+	}
 }
 
 func (l *appLogger) Tracef(format string, args ...interface{}) {
-	l.logger.Tracef(format, args...)
+	if l.sampled(log.TraceLevel, format, nil) {
+		l.entry.Tracef(format, args...)
+	}
+}
+
+// Sync flushes the logger's output when it buffers entries, such as a bufio.Writer. Entries logged
+// concurrently with Sync may not be flushed, so call it once logging has finished, before the application exits.
+func (l *appLogger) Sync() error {
+	return syncWriter(l.logger.Out)
+}
+
+// WithField returns a child logger that adds the given field to every entry it logs.
+func (l *appLogger) WithField(key string, value any) ILogger {
+	return &appLogger{level: l.level, logger: l.logger, entry: l.entry.WithField(key, value), sampler: l.sampler}
+}
+
+// WithFields returns a child logger that adds the given fields to every entry it logs.
+func (l *appLogger) WithFields(fields map[string]any) ILogger {
+	return &appLogger{level: l.level, logger: l.logger, entry: l.entry.WithFields(fields), sampler: l.sampler}
+}
+
+// WithContext returns a child logger that adds the fields stored in ctx with ContextWithFields to every entry it logs.
+func (l *appLogger) WithContext(ctx context.Context) ILogger {
+	fields := FieldsFromContext(ctx)
+	if len(fields) == 0 {
+		return l
+	}
+	return l.WithFields(fields)
 }
@@ -1,18 +1,23 @@
 package logger
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
-	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestAppLogger_GetLevel_UnknownLevel(t *testing.T) {
-	mockLogger := new(mocks.ILogger)
-	mockLogger.On("GetLevel").Return(logrus.DebugLevel)
-
 	appLogger := &appLogger{level: "unknown"}
 	level := appLogger.GetLevel()
 
@@ -20,9 +25,6 @@ func TestAppLogger_GetLevel_UnknownLevel(t *testing.T) {
 }
 
 func TestAppLogger_GetLevel_KnownLevel(t *testing.T) {
-	mockLogger := new(mocks.ILogger)
-	mockLogger.On("GetLevel").Return(logrus.InfoLevel)
-
 	appLogger := &appLogger{level: "info"}
 	level := appLogger.GetLevel()
 
@@ -31,10 +33,7 @@ func TestAppLogger_GetLevel_KnownLevel(t *testing.T) {
 
 func TestInitLogger_ProductionEnvironment(t *testing.T) {
 	cfg := &Config{LogLevel: "debug"}
-	err := os.Setenv("APP_END", "production")
-	if err != nil {
-		return
-	}
+	t.Setenv("APP_ENV", "production")
 
 	logger := InitLogger(cfg)
 
@@ -43,12 +42,237 @@ func TestInitLogger_ProductionEnvironment(t *testing.T) {
 
 func TestInitLogger_DevelopmentEnvironment(t *testing.T) {
 	cfg := &Config{LogLevel: "info"}
+	t.Setenv("APP_ENV", "development")
 
-	err := os.Setenv("APP_END", "development")
-	if err != nil {
-		return
-	}
 	logger := InitLogger(cfg)
 
 	assert.NotNil(t, logger)
 }
+
+func TestNewFormatter_FollowsAppEnvWhenFormatIsUnset(t *testing.T) {
+	t.Setenv("APP_ENV", "production")
+	assert.IsType(t, &logrus.JSONFormatter{}, newFormatter(""))
+
+	t.Setenv("APP_ENV", "development")
+	assert.IsType(t, &logrus.TextFormatter{}, newFormatter(""))
+}
+
+func TestNewFormatter_FormatOverridesAppEnv(t *testing.T) {
+	t.Setenv("APP_ENV", "development")
+	assert.IsType(t, &logrus.JSONFormatter{}, newFormatter("json"))
+
+	t.Setenv("APP_ENV", "production")
+	assert.IsType(t, &logrus.TextFormatter{}, newFormatter("text"))
+}
+
+// newBufferedLogger returns a logger writing JSON entries to the returned buffer.
+func newBufferedLogger() (*appLogger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	return newAppLogger("info", base), &buf
+}
+
+// decodeEntry decodes the single JSON entry written to buf.
+func decodeEntry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	return entry
+}
+
+func TestAppLogger_WithField_AddsFieldToEntries(t *testing.T) {
+	l, buf := newBufferedLogger()
+
+	l.WithField("correlation_id", "abc-123").Infof("loading item %d", 42)
+
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, "loading item 42", entry["msg"])
+	assert.Equal(t, "abc-123", entry["correlation_id"])
+}
+
+func TestAppLogger_WithFields_AddsFieldsWithoutChangingParent(t *testing.T) {
+	l, buf := newBufferedLogger()
+
+	child := l.WithFields(map[string]any{"user_id": "user-42", "attempt": 2})
+	child.WithField("step", "charge").Error("payment failed")
+
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, "payment failed", entry["msg"])
+	assert.Equal(t, "user-42", entry["user_id"])
+	assert.Equal(t, float64(2), entry["attempt"])
+	assert.Equal(t, "charge", entry["step"])
+
+	buf.Reset()
+	l.Info("unrelated")
+	entry = decodeEntry(t, buf)
+	assert.NotContains(t, entry, "user_id")
+	assert.NotContains(t, entry, "step")
+}
+
+func TestAppLogger_WithContext_AddsContextFields(t *testing.T) {
+	l, buf := newBufferedLogger()
+	ctx := ContextWithFields(context.Background(), map[string]any{"correlation_id": "abc-123"})
+	ctx = ContextWithFields(ctx, map[string]any{"request_id": "req-1"})
+
+	l.WithContext(ctx).Info("handled")
+
+	entry := decodeEntry(t, buf)
+	assert.Equal(t, "abc-123", entry["correlation_id"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
+
+func TestAppLogger_WithContext_ReturnsSameLoggerWithoutFields(t *testing.T) {
+	l, _ := newBufferedLogger()
+
+	assert.Same(t, l, l.WithContext(context.Background()))
+}
+
+func TestContextWithFields_DoesNotModifyParentContext(t *testing.T) {
+	parent := ContextWithFields(context.Background(), map[string]any{"correlation_id": "abc-123"})
+	child := ContextWithFields(parent, map[string]any{"user_id": "user-42"})
+
+	assert.Equal(t, map[string]any{"correlation_id": "abc-123"}, FieldsFromContext(parent))
+	assert.Equal(t, map[string]any{"correlation_id": "abc-123", "user_id": "user-42"}, FieldsFromContext(child))
+}
+
+func TestNewConfiguredLogger_WritesToConfiguredOutput(t *testing.T) {
+	var buf bytes.Buffer
+	l := newConfiguredLogger(&Config{LogLevel: "info", Format: "text", Output: &buf}, logrus.New())
+
+	l.Infof("order %s created", "o-1")
+	l.Debug("hidden below the configured level")
+
+	assert.Contains(t, buf.String(), "order o-1 created")
+	assert.NotContains(t, buf.String(), "hidden below the configured level")
+}
+
+func TestNewLogger_CreatesIndependentInstances(t *testing.T) {
+	var debugOutput, errorOutput bytes.Buffer
+	debugLogger := NewLogger(&Config{LogLevel: "debug", Format: "json", Output: &debugOutput})
+	errorLogger := NewLogger(&Config{LogLevel: "error", Format: "text", Output: &errorOutput})
+
+	debugLogger.Debug("cache miss")
+	errorLogger.Debug("cache miss")
+	errorLogger.Error("disk full")
+
+	assert.Equal(t, logrus.DebugLevel, debugLogger.GetLevel())
+	assert.Equal(t, logrus.ErrorLevel, errorLogger.GetLevel())
+	assert.Equal(t, "cache miss", decodeEntry(t, &debugOutput)["msg"])
+	assert.NotContains(t, errorOutput.String(), "cache miss")
+	assert.Contains(t, errorOutput.String(), "disk full")
+}
+
+func TestNewLogger_SamplesRepetitiveMessages(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(&Config{
+		LogLevel: "info",
+		Format:   "json",
+		Output:   &buf,
+		Sampling: &SamplingConfig{Initial: 3, Thereafter: 10, Interval: time.Hour},
+	})
+
+	for i := 0; i < 25; i++ {
+		l.Infof("request %d served", i)
+		l.WithField("attempt", i).Warn("slow query")
+	}
+	l.Info("cache warmed")
+
+	var served []string
+	var slow []float64
+	decoder := json.NewDecoder(&buf)
+	for decoder.More() {
+		var entry map[string]any
+		require.NoError(t, decoder.Decode(&entry))
+		switch entry["msg"] {
+		case "slow query":
+			slow = append(slow, entry["attempt"].(float64))
+		default:
+			served = append(served, entry["msg"].(string))
+		}
+	}
+
+	// The first 3 entries and then every 10th one are logged per message or format.
+	assert.Equal(t, []string{
+		"request 0 served", "request 1 served", "request 2 served", "request 12 served", "request 22 served", "cache warmed",
+	}, served)
+	assert.Equal(t, []float64{0, 1, 2, 12, 22}, slow)
+}
+
+func TestSampler_StartsOverEveryInterval(t *testing.T) {
+	now := time.Now()
+	s := newSampler(SamplingConfig{Initial: 1, Interval: time.Second})
+	s.now = func() time.Time { return now }
+
+	assert.True(t, s.allow(logrus.InfoLevel, "tick"))
+	assert.False(t, s.allow(logrus.InfoLevel, "tick"))
+	assert.True(t, s.allow(logrus.ErrorLevel, "tick"))
+
+	now = now.Add(time.Second)
+	assert.True(t, s.allow(logrus.InfoLevel, "tick"))
+}
+
+// closeLogFile closes the file opened for Config.Filename once the test finishes.
+func closeLogFile(t *testing.T, l ILogger) {
+	t.Cleanup(func() {
+		out := l.(*appLogger).logger.Out
+		if tee, ok := out.(teeWriter); ok {
+			out = tee[len(tee)-1]
+		}
+		_ = out.(io.Closer).Close()
+	})
+}
+
+func TestNewLogger_RotatesLogFile(t *testing.T) {
+	dir := t.TempDir()
+	l := NewLogger(&Config{
+		LogLevel:   "info",
+		Format:     "json",
+		Filename:   filepath.Join(dir, "app.log"),
+		MaxSizeMB:  1,
+		MaxBackups: 1,
+	})
+	closeLogFile(t, l)
+
+	// Write a little over 1MB so that the file is rotated once.
+	message := strings.Repeat("x", 1024)
+	for i := 0; i < 1100; i++ {
+		l.Info(message)
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	require.NoError(t, err)
+	assert.Len(t, files, 1, "expected a rotated log file")
+	assert.FileExists(t, filepath.Join(dir, "app.log"))
+}
+
+func TestAppLogger_Sync_FlushesBufferedOutput(t *testing.T) {
+	var out bytes.Buffer
+	buffered := bufio.NewWriter(&out)
+	l := NewLogger(&Config{LogLevel: "info", Format: "json", Output: buffered})
+
+	l.Info("order created")
+	require.Zero(t, out.Len(), "entry should still be buffered")
+
+	require.NoError(t, l.Sync())
+	assert.Equal(t, "order created", decodeEntry(t, &out)["msg"])
+}
+
+func TestAppLogger_Sync_FlushesOutputWrittenAlongsideFile(t *testing.T) {
+	var out bytes.Buffer
+	buffered := bufio.NewWriter(&out)
+	l := NewLogger(&Config{LogLevel: "info", Format: "json", Output: buffered, Filename: filepath.Join(t.TempDir(), "app.log")})
+	closeLogFile(t, l)
+
+	l.Info("order created")
+	require.NoError(t, l.Sync())
+
+	assert.Equal(t, "order created", decodeEntry(t, &out)["msg"])
+}
+
+func TestAppLogger_Sync_IgnoresStandardError(t *testing.T) {
+	l := NewLogger(&Config{LogLevel: "info", Output: os.Stderr})
+
+	assert.NoError(t, l.Sync())
+}
@@ -3,7 +3,11 @@
 package mocks
 
 import (
+	context "context"
+
+	logger "github.com/NekKkMirror/go-app/internal/pkg/logger"
 	logrus "github.com/sirupsen/logrus"
+
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -90,6 +94,24 @@ func (_m *ILogger) Infof(format string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// Sync provides a mock function with given fields:
+func (_m *ILogger) Sync() error {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for Sync")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func() error); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Trace provides a mock function with given fields: args
 func (_m *ILogger) Trace(args ...interface{}) {
 	var _ca []interface{}
@@ -120,6 +142,66 @@ func (_m *ILogger) Warnf(format string, args ...interface{}) {
 	_m.Called(_ca...)
 }
 
+// WithContext provides a mock function with given fields: ctx
+func (_m *ILogger) WithContext(ctx context.Context) logger.ILogger {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithContext")
+	}
+
+	var r0 logger.ILogger
+	if rf, ok := ret.Get(0).(func(context.Context) logger.ILogger); ok {
+		r0 = rf(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.ILogger)
+		}
+	}
+
+	return r0
+}
+
+// WithField provides a mock function with given fields: key, value
+func (_m *ILogger) WithField(key string, value interface{}) logger.ILogger {
+	ret := _m.Called(key, value)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithField")
+	}
+
+	var r0 logger.ILogger
+	if rf, ok := ret.Get(0).(func(string, interface{}) logger.ILogger); ok {
+		r0 = rf(key, value)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.ILogger)
+		}
+	}
+
+	return r0
+}
+
+// WithFields provides a mock function with given fields: fields
+func (_m *ILogger) WithFields(fields map[string]interface{}) logger.ILogger {
+	ret := _m.Called(fields)
+
+	if len(ret) == 0 {
+		panic("no return value specified for WithFields")
+	}
+
+	var r0 logger.ILogger
+	if rf, ok := ret.Get(0).(func(map[string]interface{}) logger.ILogger); ok {
+		r0 = rf(fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(logger.ILogger)
+		}
+	}
+
+	return r0
+}
+
 // NewILogger creates a new instance of ILogger. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewILogger(t interface {
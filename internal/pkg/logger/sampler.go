@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultSampleInterval is the default of SamplingConfig.Interval.
+const defaultSampleInterval = time.Second
+
+// SamplingConfig limits how often the same message is logged. Within every Interval the first Initial
+// entries with a given level and message are logged, and after that only every Thereafter-th one.
+// Fatal entries are never sampled.
+type SamplingConfig struct {
+	Initial int
+	// Thereafter selects every n-th entry once Initial is exceeded; zero drops them all until the next interval.
+	Thereafter int
+	// Interval is the window after which the counts start over; zero means one second.
+	Interval time.Duration
+}
+
+// sampleKey identifies the entries counted together: the level and the message, or its format string.
+type sampleKey struct {
+	level   log.Level
+	message string
+}
+
+// sampler counts the entries logged per key in the current interval. It is shared by a logger and its children.
+type sampler struct {
+	cfg SamplingConfig
+	now func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[sampleKey]int
+}
+
+func newSampler(cfg SamplingConfig) *sampler {
+	if cfg.Interval <= 0 {
+		cfg.Interval = defaultSampleInterval
+	}
+	return &sampler{cfg: cfg, now: time.Now, counts: make(map[sampleKey]int)}
+}
+
+// allow records an entry with the given level and message and reports whether it should be logged.
+func (s *sampler) allow(level log.Level, message string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now := s.now(); now.Sub(s.windowStart) >= s.cfg.Interval {
+		s.windowStart = now
+		clear(s.counts)
+	}
+
+	key := sampleKey{level: level, message: message}
+	s.counts[key]++
+	n := s.counts[key]
+
+	if n <= s.cfg.Initial {
+		return true
+	}
+	return s.cfg.Thereafter > 0 && (n-s.cfg.Initial)%s.cfg.Thereafter == 0
+}
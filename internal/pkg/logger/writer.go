@@ -0,0 +1,49 @@
+package logger
+
+import (
+	stderrors "errors"
+	"io"
+	"os"
+)
+
+// teeWriter writes every entry to all of its writers like io.MultiWriter, and unlike it can be synced.
+type teeWriter []io.Writer
+
+func (t teeWriter) Write(p []byte) (int, error) {
+	for _, w := range t {
+		n, err := w.Write(p)
+		if err != nil {
+			return n, err
+		}
+		if n != len(p) {
+			return n, io.ErrShortWrite
+		}
+	}
+	return len(p), nil
+}
+
+// Sync flushes all of the writers.
+func (t teeWriter) Sync() error {
+	var errs []error
+	for _, w := range t {
+		errs = append(errs, syncWriter(w))
+	}
+	return stderrors.Join(errs...)
+}
+
+// syncWriter flushes w when it buffers entries, either with a Sync or a Flush method. Standard output and
+// standard error are unbuffered and cannot be synced when attached to a terminal or a pipe, so they are skipped.
+func syncWriter(w io.Writer) error {
+	switch w := w.(type) {
+	case *os.File:
+		if w == os.Stdout || w == os.Stderr {
+			return nil
+		}
+		return w.Sync()
+	case interface{ Sync() error }:
+		return w.Sync()
+	case interface{ Flush() error }:
+		return w.Flush()
+	}
+	return nil
+}
@@ -1,18 +1,43 @@
 package ormpgsql
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	stdlog "log"
+	"os"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
 	"github.com/NekKkMirror/go-app/internal/pkg/utils/db/pagination"
 	"github.com/cenkalti/backoff/v4"
+	"github.com/jackc/pgx/v5"
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun/driver/pgdriver"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+	"gorm.io/plugin/dbresolver"
 )
 
+// ErrInvalidDBName is returned when the configured database name is not a safe identifier.
+var ErrInvalidDBName = errors.New("invalid database name")
+
+// dbNamePattern matches unquoted postgres identifiers up to the 63 byte NAMEDATALEN limit.
+var dbNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// Defaults applied to the connection retry policy when the configuration leaves it unset.
+const (
+	defaultRetryMaxElapsedTime = 10 * time.Second
+	defaultRetryMaxAttempts    = 5
+)
+
+// duplicateDatabaseCode is the SQLSTATE postgres returns when creating a database that already exists.
+const duplicateDatabaseCode = "42P04"
+
 // PostgresConfig holds the configuration parameters for the PostgreSQL connection.
 type PostgresConfig struct {
 	Host     string `mapstructure:"host"`
@@ -21,6 +46,29 @@ type PostgresConfig struct {
 	DBName   string `mapstructure:"dbName"`
 	SSLMode  bool   `mapstructure:"sslMode"`
 	Password string `mapstructure:"password"`
+
+	// Connection pool settings; zero values keep the database/sql defaults.
+	MaxOpenConns    int           `mapstructure:"maxOpenConns"`
+	MaxIdleConns    int           `mapstructure:"maxIdleConns"`
+	ConnMaxLifetime time.Duration `mapstructure:"connMaxLifetime"`
+	ConnMaxIdleTime time.Duration `mapstructure:"connMaxIdleTime"`
+
+	// Connection retry policy; zero values fall back to 10s and 5 attempts.
+	RetryMaxElapsedTime time.Duration `mapstructure:"retryMaxElapsedTime"`
+	RetryMaxAttempts    int           `mapstructure:"retryMaxAttempts"`
+
+	// PrepareStmt caches prepared statements for reuse by identical queries.
+	PrepareStmt bool `mapstructure:"prepareStmt"`
+
+	// UTCTimestamps makes gorm fill CreatedAt and UpdatedAt fields with the current time in UTC.
+	UTCTimestamps bool `mapstructure:"utcTimestamps"`
+
+	// SlowThreshold is the duration above which queries are logged as warnings; zero disables it.
+	// Without a logger passed to NewWithLogger the warnings go to standard output like gorm's default logger.
+	SlowThreshold time.Duration `mapstructure:"slowThreshold"`
+
+	// ReadReplicas receive read queries while writes and transactions stay on the primary.
+	ReadReplicas []PostgresConfig `mapstructure:"readReplicas"`
 }
 
 // ORM represents an object-relational mapper with a GORM DB connection and configuration.
@@ -29,43 +77,216 @@ type ORM struct {
 	config *PostgresConfig
 }
 
-// NewORM initializes and returns a new ORM instance with a connected GORM database.
-// It handles connection retries using exponential backoff and ensures the database exists.
+// NewORM initializes and returns a connected GORM database.
+// It is kept for callers that only need the *gorm.DB; use New to get the ORM wrapper.
 func NewORM(cfg *PostgresConfig) (*gorm.DB, error) {
+	orm, err := New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return orm.DB, nil
+}
+
+// New initializes and returns a new ORM instance with a connected GORM database.
+// It handles connection retries using exponential backoff and ensures the database exists.
+func New(cfg *PostgresConfig) (*ORM, error) {
+	return NewWithLogger(cfg, nil)
+}
+
+// NewWithLogger is like New but routes gorm's logs, including slow-query warnings, through log.
+// A nil log keeps gorm's default logger, using SlowThreshold when it is set.
+func NewWithLogger(cfg *PostgresConfig, log logger.ILogger) (*ORM, error) {
 	if cfg.DBName == "" {
 		return nil, errors.New("database name is required")
 	}
 
-	if err := createDB(cfg); err != nil {
+	if err := validateDBName(cfg.DBName); err != nil {
 		return nil, err
 	}
 
-	dataSrcName := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName)
+	dataSrcName := dataSourceName(cfg)
 
 	bo := backoff.NewExponentialBackOff()
-	bo.MaxElapsedTime = 10 * time.Second
-	maxRetries := 5
+	bo.MaxElapsedTime = cfg.retryMaxElapsedTime()
+	maxAttempts := cfg.retryMaxAttempts()
 
 	var db *gorm.DB
+	attempts := 0
 	err := backoff.Retry(func() error {
+		attempts++
+		if err := createDB(cfg); err != nil {
+			return err
+		}
+
 		var err error
-		db, err = gorm.Open(postgres.Open(dataSrcName), &gorm.Config{})
+		db, err = gorm.Open(postgres.Open(dataSrcName), gormConfig(cfg, log))
 		if err != nil {
 			return errors.Wrapf(err, "failed to connect to postgres: %s", dataSrcName)
 		}
 		return nil
-	}, backoff.WithMaxRetries(bo, uint64(maxRetries-1)))
+	}, backoff.WithMaxRetries(bo, uint64(maxAttempts-1)))
 
 	if err != nil {
+		return nil, errors.Wrapf(err, "giving up after %d attempts", attempts)
+	}
+
+	orm := &ORM{DB: db, config: cfg}
+	if err := configureDB(db, cfg); err != nil {
+		// Release the connections opened above rather than leaking them with the unusable ORM.
+		_ = orm.Close()
+		return nil, err
+	}
+
+	return orm, nil
+}
+
+// configureDB applies the pool settings, read replicas and tracing to a freshly opened database.
+func configureDB(db *gorm.DB, cfg *PostgresConfig) error {
+	if err := configurePool(db, cfg); err != nil {
+		return err
+	}
+
+	if err := useReadReplicas(db, cfg); err != nil {
+		return err
+	}
+
+	return RegisterTracing(db)
+}
+
+// NewORMWithDB returns a GORM database that uses an already open *sql.DB, such as a shared pool or sqlmock,
+// instead of dialing postgres. The database is neither created nor retried; cfg may be nil.
+func NewORMWithDB(sqlDB *sql.DB, cfg *PostgresConfig) (*gorm.DB, error) {
+	if cfg == nil {
+		cfg = &PostgresConfig{}
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), gormConfig(cfg, nil))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to open gorm on the provided connection")
+	}
+
+	if err := configureDB(db, cfg); err != nil {
 		return nil, err
 	}
 
 	return db, nil
 }
 
+// gormConfig builds the gorm configuration from cfg, wiring log in as the gorm logger when it is provided.
+// Otherwise a non-zero SlowThreshold replaces the 200ms threshold of gorm's default logger.
+func gormConfig(cfg *PostgresConfig, log logger.ILogger) *gorm.Config {
+	config := &gorm.Config{PrepareStmt: cfg.PrepareStmt}
+	if cfg.UTCTimestamps {
+		config.NowFunc = func() time.Time { return time.Now().UTC() }
+	}
+	switch {
+	case log != nil:
+		config.Logger = NewGormLogger(log, cfg.SlowThreshold)
+	case cfg.SlowThreshold > 0:
+		config.Logger = gormlogger.New(stdlog.New(os.Stdout, "\r\n", stdlog.LstdFlags), gormlogger.Config{
+			SlowThreshold: cfg.SlowThreshold,
+			LogLevel:      gormlogger.Warn,
+			Colorful:      true,
+		})
+	}
+	return config
+}
+
+// retryMaxElapsedTime returns the configured retry budget or its default.
+func (cfg *PostgresConfig) retryMaxElapsedTime() time.Duration {
+	if cfg.RetryMaxElapsedTime <= 0 {
+		return defaultRetryMaxElapsedTime
+	}
+	return cfg.RetryMaxElapsedTime
+}
+
+// retryMaxAttempts returns the configured number of connection attempts or its default.
+func (cfg *PostgresConfig) retryMaxAttempts() int {
+	if cfg.RetryMaxAttempts <= 0 {
+		return defaultRetryMaxAttempts
+	}
+	return cfg.RetryMaxAttempts
+}
+
+// dataSourceName builds the postgres connection string for the given configuration.
+func dataSourceName(cfg *PostgresConfig) string {
+	return fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s",
+		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName)
+}
+
+// useReadReplicas registers the configured read replicas with gorm's dbresolver plugin,
+// which routes queries to the replicas and writes to the primary.
+func useReadReplicas(db *gorm.DB, cfg *PostgresConfig) error {
+	if len(cfg.ReadReplicas) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(cfg.ReadReplicas))
+	for i := range cfg.ReadReplicas {
+		replicas = append(replicas, postgres.Open(dataSourceName(&cfg.ReadReplicas[i])))
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	})
+	if err := db.Use(resolver); err != nil {
+		return errors.Wrap(err, "failed to register read replicas")
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		resolver.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		resolver.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		resolver.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		resolver.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	return nil
+}
+
+// configurePool applies the connection pool settings from the configuration to the underlying *sql.DB.
+func configurePool(db *gorm.DB, cfg *PostgresConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve db from gorm DB")
+	}
+
+	if cfg.MaxOpenConns > 0 {
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	}
+	if cfg.MaxIdleConns > 0 {
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	}
+	if cfg.ConnMaxLifetime > 0 {
+		sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	}
+	if cfg.ConnMaxIdleTime > 0 {
+		sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+	}
+
+	return nil
+}
+
+// validateDBName ensures the database name is a plain identifier that is safe to use in SQL statements.
+func validateDBName(name string) error {
+	if !dbNamePattern.MatchString(name) {
+		return errors.Wrapf(ErrInvalidDBName, "%q", name)
+	}
+	return nil
+}
+
 // createDB creates the database if it does not already exist, based on the provided configuration.
 func createDB(cfg *PostgresConfig) error {
+	if err := validateDBName(cfg.DBName); err != nil {
+		return err
+	}
+
 	// DSN without specifying a database to connect on server level
 	dsn := fmt.Sprintf("postgres://%s:%s@%s:%d?sslmode=disable",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port)
@@ -74,8 +295,8 @@ func createDB(cfg *PostgresConfig) error {
 	defer sqldb.Close()
 
 	var exists bool
-	query := fmt.Sprintf("SELECT 1 FROM pg_catalog.pg_database WHERE datname='%s'", cfg.DBName)
-	if err := sqldb.QueryRow(query).Scan(&exists); err != nil {
+	query := "SELECT 1 FROM pg_catalog.pg_database WHERE datname = $1"
+	if err := sqldb.QueryRow(query, cfg.DBName).Scan(&exists); err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return errors.Wrap(err, "failed to check database existence")
 		}
@@ -86,14 +307,29 @@ func createDB(cfg *PostgresConfig) error {
 		return nil
 	}
 
-	createDBQuery := fmt.Sprintf("CREATE DATABASE %s", cfg.DBName)
+	createDBQuery := fmt.Sprintf("CREATE DATABASE %s", pgx.Identifier{cfg.DBName}.Sanitize())
 	if _, err := sqldb.Exec(createDBQuery); err != nil {
+		// Another process may have created the database between the check and the create.
+		if isDuplicateDatabase(err) {
+			return nil
+		}
 		return errors.Wrap(err, "failed to create database")
 	}
 
 	return nil
 }
 
+// isDuplicateDatabase reports whether err is the postgres duplicate_database (42P04) error.
+func isDuplicateDatabase(err error) bool {
+	var pgErr pgdriver.Error
+	return errors.As(err, &pgErr) && pgErr.Field('C') == duplicateDatabaseCode
+}
+
+// Config returns the configuration the ORM was created with.
+func (orm *ORM) Config() *PostgresConfig {
+	return orm.config
+}
+
 // Close closes the GORM database connection associated with the ORM instance.
 func (orm *ORM) Close() error {
 	db, err := orm.DB.DB()
@@ -103,33 +339,139 @@ func (orm *ORM) Close() error {
 	return db.Close()
 }
 
+// Ping verifies that the database is reachable, which makes it suitable for readiness probes.
+func (orm *ORM) Ping(ctx context.Context) error {
+	db, err := orm.DB.DB()
+	if err != nil {
+		return errors.Wrap(err, "failed to retrieve db from gorm DB")
+	}
+	return db.PingContext(ctx)
+}
+
+// Stats returns the connection pool statistics of the underlying database for monitoring.
+func (orm *ORM) Stats() sql.DBStats {
+	db, err := orm.DB.DB()
+	if err != nil {
+		return sql.DBStats{}
+	}
+	return db.Stats()
+}
+
 // Paginate fetches the records as per the pagination and filter criteria.
-func Paginate[T any](listQuery *pagination.ListQuery, DB *gorm.DB) (*pagination.ListResult[T], error) {
+// The result counts both every record and those matching the filters; pagination follows the filtered count,
+// and the page query is skipped when nothing matches.
+func Paginate[T any](ctx context.Context, listQuery *pagination.ListQuery, DB *gorm.DB) (*pagination.ListResult[T], error) {
 	var data []T
-	var totalCount int64
-	var query *gorm.DB
+	var totalCount, filteredCount int64
 	var err error
 
-	if err = DB.Model(new(T)).Count(&totalCount).Error; err != nil {
-		return nil, errors.Wrap(err, "failed to count total records")
+	DB = DB.WithContext(ctx)
+
+	filtered := DB.Model(new(T))
+	if listQuery.Filters != nil {
+		filtered, err = pagination.ApplyFilterAction(filtered, listQuery.Filters, make(map[string]bool))
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to apply filters")
+		}
 	}
+	// A fresh session lets the count and the page query each build on the filters independently.
+	filtered = filtered.Session(&gorm.Session{})
 
-	query = DB.Offset(listQuery.GetOffset()).
+	query := filtered.Offset(listQuery.GetOffset()).
 		Limit(listQuery.GetLimit()).
 		Order(listQuery.GetOrderBy())
 
-	if listQuery.Filters != nil {
-		query, err = pagination.ApplyFilterAction(query, listQuery.Filters, make(map[string]bool))
-		if err != nil {
+	if columns := listQuery.GetSelect(); len(columns) > 0 {
+		if err = validateColumns[T](DB, columns); err != nil {
 			return nil, err
 		}
+		query = query.Select(columns)
+	}
+
+	if err = validateAssociations[T](DB, listQuery.GetJoins()); err != nil {
+		return nil, err
+	}
+	for _, join := range listQuery.GetJoins() {
+		query = query.Joins(join)
+	}
+
+	if err = validateAssociations[T](DB, listQuery.GetPreloads()); err != nil {
+		return nil, err
+	}
+	for _, preload := range listQuery.GetPreloads() {
+		query = query.Preload(preload)
+	}
+
+	if err = filtered.Count(&filteredCount).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to count filtered records")
+	}
+
+	totalCount = filteredCount
+	if len(listQuery.Filters) > 0 {
+		if err = DB.Model(new(T)).Count(&totalCount).Error; err != nil {
+			return nil, errors.Wrap(err, "failed to count total records")
+		}
+	}
+
+	if filteredCount == 0 {
+		return pagination.NewFilteredListResult(listQuery.Size, listQuery.Page, totalCount, filteredCount, make([]T, 0)), nil
 	}
 
 	if err = query.Find(&data).Error; err != nil {
 		return nil, errors.Wrap(err, "failed to fetch data")
 	}
 
-	listResult := pagination.NewListResult(listQuery.Size, listQuery.Page, totalCount, data)
+	listResult := pagination.NewFilteredListResult(listQuery.Size, listQuery.Page, totalCount, filteredCount, data)
 
 	return listResult, nil
 }
+
+// validateColumns ensures that every column belongs to the schema of T, which acts as the allow-list
+// for caller-supplied column names.
+func validateColumns[T any](DB *gorm.DB, columns []string) error {
+	modelSchema, err := parseSchema[T](DB)
+	if err != nil {
+		return err
+	}
+
+	for _, column := range columns {
+		if _, ok := modelSchema.FieldsByDBName[column]; !ok {
+			return errors.Errorf("column %s is not allowed", column)
+		}
+	}
+	return nil
+}
+
+// validateAssociations ensures that every name, including each segment of a nested name such as "Orders.Items",
+// is an association declared on the schema of T.
+func validateAssociations[T any](DB *gorm.DB, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	modelSchema, err := parseSchema[T](DB)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		current := modelSchema
+		for _, part := range strings.Split(name, ".") {
+			relationship, ok := current.Relationships.Relations[part]
+			if !ok {
+				return errors.Errorf("association %s is not allowed", name)
+			}
+			current = relationship.FieldSchema
+		}
+	}
+	return nil
+}
+
+// parseSchema parses the gorm schema of T.
+func parseSchema[T any](DB *gorm.DB) (*schema.Schema, error) {
+	stmt := &gorm.Statement{DB: DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, errors.Wrap(err, "failed to parse model schema")
+	}
+	return stmt.Schema, nil
+}
@@ -2,41 +2,192 @@ package ormpgsql
 
 import (
 	"context"
+	"reflect"
 	"time"
 
+	"github.com/NekKkMirror/go-app/internal/pkg/utils/db/pagination"
+	"github.com/pkg/errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
 )
 
+// defaultBatchSize is the number of rows written per statement by batched operations.
+const defaultBatchSize = 100
+
+// notDeletedCondition matches rows of the current table whose deleted_at is not set.
+var notDeletedCondition = clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: "deleted_at"}, Value: nil}
+
+var (
+	// ErrMissingKeys is returned when a key-based lookup or delete is called without any key columns.
+	ErrMissingKeys = errors.New("at least one key column is required")
+
+	// ErrNotFound is returned when no record matches a lookup. It also matches gorm.ErrRecordNotFound.
+	ErrNotFound = errors.New("record not found")
+
+	// ErrOptimisticLock is returned when a versioned update finds the row changed by someone else.
+	ErrOptimisticLock = errors.New("record was modified concurrently")
+)
+
+// notFoundError reports gorm's not-found error as ErrNotFound while keeping it in the error chain.
+type notFoundError struct {
+	err error
+}
+
+func (e *notFoundError) Error() string {
+	return e.err.Error()
+}
+
+// Is lets errors.Is match ErrNotFound.
+func (e *notFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// Unwrap exposes the underlying gorm error.
+func (e *notFoundError) Unwrap() error {
+	return e.err
+}
+
+// wrapNotFound converts gorm.ErrRecordNotFound into an error matching ErrNotFound and returns other errors unchanged.
+func wrapNotFound(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &notFoundError{err: err}
+	}
+	return err
+}
+
 // GenericRepository provides a generic repository for CRUD operations on any entity type.
 type GenericRepository[T any] struct {
-	DB *gorm.DB
+	DB        *gorm.DB
+	BatchSize int
+	Timeout   time.Duration
 }
 
 // NewGenericRepository creates a new instance of GenericRepository.
 func NewGenericRepository[T any](DB *gorm.DB) *GenericRepository[T] {
-	return &GenericRepository[T]{DB: DB}
+	return &GenericRepository[T]{DB: DB, BatchSize: defaultBatchSize}
 }
 
-// Create inserts a new record into the database.
+// WithBatchSize returns a copy of the repository that writes batchSize rows per statement in batched operations.
+func (r *GenericRepository[T]) WithBatchSize(batchSize int) *GenericRepository[T] {
+	repo := *r
+	repo.BatchSize = batchSize
+	return &repo
+}
+
+// WithTimeout returns a copy of the repository that bounds every call by the given timeout.
+// A zero timeout leaves the caller's context untouched.
+func (r *GenericRepository[T]) WithTimeout(timeout time.Duration) *GenericRepository[T] {
+	repo := *r
+	repo.Timeout = timeout
+	return &repo
+}
+
+// WithTx returns a copy of the repository bound to the given transaction.
+func (r *GenericRepository[T]) WithTx(tx *gorm.DB) *GenericRepository[T] {
+	repo := *r
+	repo.DB = tx
+	return &repo
+}
+
+// Transaction runs fn inside a database transaction, committing when fn returns nil
+// and rolling back when it returns an error or panics.
+func Transaction(ctx context.Context, DB *gorm.DB, fn func(tx *gorm.DB) error) error {
+	return DB.WithContext(ctx).Transaction(fn)
+}
+
+// Create inserts a new record into the database and populates entity with every returned column,
+// including database-generated values.
 func (r *GenericRepository[T]) Create(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Create(entity).Error
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Clauses(clause.Returning{}).Create(entity).Error
 }
 
-// CreateMany inserts multiple records into the database.
+// CreateMany inserts multiple records into the database and populates them with every returned column.
 func (r *GenericRepository[T]) CreateMany(ctx context.Context, entities *[]T) error {
-	return r.DB.WithContext(ctx).Create(entities).Error
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Clauses(clause.Returning{}).Create(entities).Error
+}
+
+// CreateInBatches inserts the entities batchSize rows per statement within a single transaction,
+// keeping large inserts under postgres' bind parameter limit. A non-positive batchSize uses BatchSize.
+func (r *GenericRepository[T]) CreateInBatches(ctx context.Context, entities *[]T, batchSize int) error {
+	if batchSize <= 0 {
+		batchSize = r.batchSize()
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.Returning{}).
+			CreateInBatches(entities, batchSize).
+			Error
+	})
 }
 
 // GetById retrieves a single record based on the provided ID.
 func (r *GenericRepository[T]) GetById(ctx context.Context, id string) (*T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	err := r.DB.WithContext(ctx).
 		Model(&entity).
-		Where("id = ? AND deleted_at IS NULL", id).
+		Scopes(r.notDeleted).
+		Where("id = ?", id).
 		First(&entity).
 		Error
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
+	}
+
+	return &entity, nil
+}
+
+// GetByIdForUpdate retrieves a single record and locks its row with SELECT ... FOR UPDATE.
+// The lock is held until the surrounding transaction ends, so call it on a repository bound with WithTx.
+func (r *GenericRepository[T]) GetByIdForUpdate(ctx context.Context, id string) (*T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	err := r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+		Where("id = ?", id).
+		First(&entity).
+		Error
+	if err != nil {
+		return nil, wrapNotFound(err)
+	}
+
+	return &entity, nil
+}
+
+// GetByKeys retrieves a single record identified by several key columns, such as the composite primary key of a join table.
+func (r *GenericRepository[T]) GetByKeys(ctx context.Context, keys map[string]any) (*T, error) {
+	if len(keys) == 0 {
+		return nil, ErrMissingKeys
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	err := r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Where(keys).
+		First(&entity).
+		Error
+	if err != nil {
+		return nil, wrapNotFound(err)
 	}
 
 	return &entity, nil
@@ -44,22 +195,47 @@ func (r *GenericRepository[T]) GetById(ctx context.Context, id string) (*T, erro
 
 // Get retrieves a single record based on the provided parameters.
 func (r *GenericRepository[T]) Get(ctx context.Context, params *T) (*T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	err := r.DB.WithContext(ctx).
+		Scopes(r.notDeleted).
 		Where(params).
 		First(&entity).
 		Error
 	if err != nil {
-		return nil, err
+		return nil, wrapNotFound(err)
 	}
 
 	return &entity, nil
 }
 
+// FindWhereFilters retrieves every record matching the filters, using the same comparisons as Paginate.
+func (r *GenericRepository[T]) FindWhereFilters(ctx context.Context, filters []*pagination.FilterModel) (*[]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	query, err := pagination.ApplyFilterAction(r.DB.WithContext(ctx).Scopes(r.notDeleted), filters, make(map[string]bool))
+	if err != nil {
+		return nil, err
+	}
+
+	var entities []T
+	if err := query.Find(&entities).Error; err != nil {
+		return nil, err
+	}
+	return &entities, nil
+}
+
 // GetAll retrieves all records from the database.
 func (r *GenericRepository[T]) GetAll(ctx context.Context) (*[]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entities []T
 	err := r.DB.WithContext(ctx).
+		Scopes(r.notDeleted).
 		Find(&entities).
 		Error
 	if err != nil {
@@ -70,8 +246,12 @@ func (r *GenericRepository[T]) GetAll(ctx context.Context) (*[]T, error) {
 
 // Where retrieves records based on the provided parameters.
 func (r *GenericRepository[T]) Where(ctx context.Context, params *T) (*[]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entities []T
 	err := r.DB.WithContext(ctx).
+		Scopes(r.notDeleted).
 		Where(params).
 		Find(&entities).
 		Error
@@ -82,40 +262,212 @@ func (r *GenericRepository[T]) Where(ctx context.Context, params *T) (*[]T, erro
 }
 
 // Update modifies an existing record in the database.
+// When T has an int Version field, the update only applies if the stored version still matches
+// and the record is not soft deleted, increments the version and returns ErrOptimisticLock otherwise.
 func (r *GenericRepository[T]) Update(ctx context.Context, entity *T) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	if version := r.versionField(); version != nil {
+		return r.updateVersioned(ctx, entity, version)
+	}
 	return r.DB.WithContext(ctx).Save(entity).Error
 }
 
-// UpdateMany modifies multiple records in the database.
-func (r *GenericRepository[T]) UpdateMany(ctx context.Context, entities *[]T) error {
-	tx := r.DB.WithContext(ctx).Begin()
-	for _, entity := range *entities {
-		if err := tx.Save(&entity).Error; err != nil {
-			tx.Rollback()
+// updateVersioned saves every column of entity guarded by its current version, bumping the version on success.
+func (r *GenericRepository[T]) updateVersioned(ctx context.Context, entity *T, version *schema.Field) error {
+	value := reflect.ValueOf(entity).Elem()
+	current, _ := version.ValueOf(ctx, value)
+
+	if err := version.Set(ctx, value, current.(int)+1); err != nil {
+		return err
+	}
+
+	result := r.DB.WithContext(ctx).
+		Model(entity).
+		Scopes(r.notDeleted).
+		Select("*").
+		Where(clause.Eq{Column: clause.Column{Table: clause.CurrentTable, Name: version.DBName}, Value: current}).
+		Updates(entity)
+	if result.Error == nil && result.RowsAffected == 0 {
+		result.Error = ErrOptimisticLock
+	}
+	if result.Error != nil {
+		if err := version.Set(ctx, value, current); err != nil {
 			return err
 		}
+		return result.Error
+	}
+	return nil
+}
+
+// Patch updates only the given columns of the record with the provided ID, leaving every other column untouched.
+// It returns ErrNotFound when no record has that ID or the record has been soft deleted.
+func (r *GenericRepository[T]) Patch(ctx context.Context, id string, fields map[string]any) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	columns := make([]string, 0, len(fields))
+	for column := range fields {
+		columns = append(columns, column)
+	}
+	if err := validateColumns[T](r.DB, columns); err != nil {
+		return err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	result := r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Where("id = ?", id).
+		Updates(fields)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return wrapNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// UpdateMany modifies multiple records in the database.
+// Rows are upserted by primary key in batches of BatchSize within a single transaction,
+// so an error in any batch rolls back the whole operation. Soft-deleted rows are left untouched.
+func (r *GenericRepository[T]) UpdateMany(ctx context.Context, entities *[]T) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	onConflict := clause.OnConflict{UpdateAll: true}
+	if r.hasSoftDelete() {
+		onConflict.Where = clause.Where{Exprs: []clause.Expression{notDeletedCondition}}
 	}
-	return tx.Commit().Error
+
+	return r.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(onConflict).
+			CreateInBatches(entities, r.batchSize()).
+			Error
+	})
 }
 
-// Delete sets the deleted_at timestamp for soft deletion.
+// Delete sets the deleted_at timestamp for soft deletion, returning ErrNotFound when the record is missing
+// or already deleted. Entities without a deleted_at column are removed permanently instead.
 func (r *GenericRepository[T]) Delete(ctx context.Context, entityId string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
-	err := r.DB.WithContext(ctx).
+	if !r.hasSoftDelete() {
+		return r.DB.WithContext(ctx).
+			Where("id = ?", entityId).
+			Delete(&entity).
+			Error
+	}
+
+	result := r.DB.WithContext(ctx).
 		Model(&entity).
+		Scopes(r.notDeleted).
 		Where("id = ?", entityId).
+		UpdateColumn("deleted_at", time.Now().UTC())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return wrapNotFound(gorm.ErrRecordNotFound)
+	}
+	return nil
+}
+
+// DeleteByKeys soft deletes the record identified by several key columns.
+// Entities without a deleted_at column are removed permanently instead.
+func (r *GenericRepository[T]) DeleteByKeys(ctx context.Context, keys map[string]any) error {
+	if len(keys) == 0 {
+		return ErrMissingKeys
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	if !r.hasSoftDelete() {
+		return r.DB.WithContext(ctx).
+			Where(keys).
+			Delete(&entity).
+			Error
+	}
+
+	return r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Where(keys).
 		UpdateColumn("deleted_at", time.Now().UTC()).
 		Error
-	if err != nil {
-		return err
+}
+
+// DeleteMany soft deletes the records with the given IDs in a single statement.
+// Entities without a deleted_at column are removed permanently instead.
+func (r *GenericRepository[T]) DeleteMany(ctx context.Context, entityIds []string) error {
+	if len(entityIds) == 0 {
+		return nil
 	}
-	return nil
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	if !r.hasSoftDelete() {
+		return r.DB.WithContext(ctx).
+			Where("id IN ?", entityIds).
+			Delete(&entity).
+			Error
+	}
+
+	return r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Where("id IN ?", entityIds).
+		UpdateColumn("deleted_at", time.Now().UTC()).
+		Error
+}
+
+// HardDelete permanently removes a record, regardless of whether it supports soft deletion.
+func (r *GenericRepository[T]) HardDelete(ctx context.Context, entityId string) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	return r.DB.WithContext(ctx).
+		Unscoped().
+		Where("id = ?", entityId).
+		Delete(&entity).
+		Error
+}
+
+// HardDeleteWhere permanently removes the records that match the provided parameters.
+// Parameters without any non-zero field are rejected by gorm rather than deleting every row.
+func (r *GenericRepository[T]) HardDeleteWhere(ctx context.Context, params *T) error {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	return r.DB.WithContext(ctx).
+		Unscoped().
+		Where(params).
+		Delete(&entity).
+		Error
 }
 
 // SkipTake retrieves records with pagination support.
 func (r *GenericRepository[T]) SkipTake(ctx context.Context, skip int, take int) (*[]T, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entities []T
 	err := r.DB.WithContext(ctx).
+		Scopes(r.notDeleted).
 		Offset(skip).
 		Limit(take).
 		Find(&entities).
@@ -126,12 +478,48 @@ func (r *GenericRepository[T]) SkipTake(ctx context.Context, skip int, take int)
 	return &entities, nil
 }
 
+// EachBatch streams the records in primary key order, passing at most batchSize of them to fn at a time
+// so large tables can be processed without loading them into memory. Soft-deleted rows are skipped and
+// iteration stops at the first error returned by fn or once the context is cancelled.
+func (r *GenericRepository[T]) EachBatch(ctx context.Context, batchSize int, fn func(batch []T) error) error {
+	if batchSize <= 0 {
+		batchSize = r.batchSize()
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var batch []T
+	return r.DB.WithContext(ctx).
+		Scopes(r.notDeleted).
+		FindInBatches(&batch, batchSize, func(_ *gorm.DB, _ int) error {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			return fn(batch)
+		}).
+		Error
+}
+
+// FindPaginated retrieves a page of records matching the filters of the list query,
+// excluding soft-deleted rows, together with pagination metadata.
+func (r *GenericRepository[T]) FindPaginated(ctx context.Context, listQuery *pagination.ListQuery) (*pagination.ListResult[T], error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return Paginate[T](ctx, listQuery, r.DB.Scopes(r.notDeleted))
+}
+
 // Count returns the total number of records.
 func (r *GenericRepository[T]) Count(ctx context.Context) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	var count int64
 	err := r.DB.WithContext(ctx).
 		Model(&entity).
+		Scopes(r.notDeleted).
 		Count(&count).
 		Error
 	if err != nil {
@@ -142,10 +530,14 @@ func (r *GenericRepository[T]) Count(ctx context.Context) (int64, error) {
 
 // CountWhere returns the number of records that match the provided parameters.
 func (r *GenericRepository[T]) CountWhere(ctx context.Context, params *T) (int64, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
 	var entity T
 	var count int64
 	err := r.DB.WithContext(ctx).
 		Model(&entity).
+		Scopes(r.notDeleted).
 		Where(params).
 		Count(&count).
 		Error
@@ -154,3 +546,106 @@ func (r *GenericRepository[T]) CountWhere(ctx context.Context, params *T) (int64
 	}
 	return count, nil
 }
+
+// CountDistinct returns the number of distinct values stored in column.
+func (r *GenericRepository[T]) CountDistinct(ctx context.Context, column string) (int64, error) {
+	if err := validateColumns[T](r.DB, []string{column}); err != nil {
+		return 0, err
+	}
+
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	var entity T
+	var count int64
+	err := r.DB.WithContext(ctx).
+		Model(&entity).
+		Scopes(r.notDeleted).
+		Distinct(column).
+		Count(&count).
+		Error
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Exists reports whether any record matches the provided parameters.
+func (r *GenericRepository[T]) Exists(ctx context.Context, params *T) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.exists(r.DB.WithContext(ctx).Where(params))
+}
+
+// ExistsById reports whether a record with the provided ID exists.
+func (r *GenericRepository[T]) ExistsById(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := r.withTimeout(ctx)
+	defer cancel()
+
+	return r.exists(r.DB.WithContext(ctx).Where("id = ?", id))
+}
+
+// exists runs SELECT 1 ... LIMIT 1 for the given query, ignoring soft-deleted rows.
+func (r *GenericRepository[T]) exists(db *gorm.DB) (bool, error) {
+	var entity T
+	var found int
+	result := db.Model(&entity).
+		Scopes(r.notDeleted).
+		Select("1").
+		Limit(1).
+		Scan(&found)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// withTimeout derives a context bounded by the repository timeout, if one is configured.
+func (r *GenericRepository[T]) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if r.Timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, r.Timeout)
+}
+
+// batchSize returns the configured batch size, falling back to the default when unset.
+func (r *GenericRepository[T]) batchSize() int {
+	if r.BatchSize <= 0 {
+		return defaultBatchSize
+	}
+	return r.BatchSize
+}
+
+// notDeleted restricts a query to rows that have not been soft deleted.
+// It leaves the query untouched for entities without a deleted_at column. The column is qualified
+// with the table of T, so that it stays unambiguous when joined tables have a deleted_at column too.
+func (r *GenericRepository[T]) notDeleted(db *gorm.DB) *gorm.DB {
+	if !r.hasSoftDelete() {
+		return db
+	}
+	return db.Where(notDeletedCondition)
+}
+
+// versionField returns the int Version field of T used for optimistic locking, or nil when T has none.
+func (r *GenericRepository[T]) versionField() *schema.Field {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil
+	}
+
+	field := stmt.Schema.LookUpField("version")
+	if field == nil || field.FieldType.Kind() != reflect.Int {
+		return nil
+	}
+	return field
+}
+
+// hasSoftDelete reports whether T maps to a table with a deleted_at column.
+func (r *GenericRepository[T]) hasSoftDelete() bool {
+	stmt := &gorm.Statement{DB: r.DB}
+	if err := stmt.Parse(new(T)); err != nil {
+		return false
+	}
+	return stmt.Schema.LookUpField("deleted_at") != nil
+}
@@ -0,0 +1,74 @@
+package ormpgsql
+
+import (
+	"context"
+	"time"
+
+	"github.com/NekKkMirror/go-app/internal/pkg/logger"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// gormLogger adapts the application ILogger to gorm's logger interface.
+type gormLogger struct {
+	log           logger.ILogger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+}
+
+// NewGormLogger returns a gorm logger that writes through log at warn level and reports
+// queries slower than slowThreshold as warnings. A zero threshold disables slow-query logging.
+func NewGormLogger(log logger.ILogger, slowThreshold time.Duration) gormlogger.Interface {
+	return &gormLogger{log: log, level: gormlogger.Warn, slowThreshold: slowThreshold}
+}
+
+// LogMode returns a copy of the logger that logs at the given level.
+func (l *gormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	newLogger := *l
+	newLogger.level = level
+	return &newLogger
+}
+
+// Info logs a gorm info message.
+func (l *gormLogger) Info(_ context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.log.Infof(msg, data...)
+	}
+}
+
+// Warn logs a gorm warning.
+func (l *gormLogger) Warn(_ context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.log.Warnf(msg, data...)
+	}
+}
+
+// Error logs a gorm error.
+func (l *gormLogger) Error(_ context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.log.Errorf(msg, data...)
+	}
+}
+
+// Trace logs failed queries as errors, slow queries as warnings and, at info level, every other query.
+func (l *gormLogger) Trace(_ context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	milliseconds := float64(elapsed.Nanoseconds()) / 1e6
+
+	switch {
+	case err != nil && l.level >= gormlogger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.log.Errorf("%s [%.3fms] [rows:%d] %s", err, milliseconds, rows, sql)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		sql, rows := fc()
+		l.log.Warnf("slow query over %s [%.3fms] [rows:%d] %s", l.slowThreshold, milliseconds, rows, sql)
+	case l.level >= gormlogger.Info:
+		sql, rows := fc()
+		l.log.Infof("[%.3fms] [rows:%d] %s", milliseconds, rows, sql)
+	}
+}
@@ -0,0 +1,198 @@
+package ormpgsql
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ErrIrreversibleMigration is returned when rolling back a migration that has no Down step.
+var ErrIrreversibleMigration = errors.New("migration cannot be rolled back")
+
+// Migrate creates or updates the tables of the given models one at a time using gorm's AutoMigrate.
+// It stops before the next model once ctx is done, and names the model that failed in the returned error.
+func Migrate(ctx context.Context, gORM *gorm.DB, types ...interface{}) error {
+	for _, t := range types {
+		if err := ctx.Err(); err != nil {
+			return errors.Wrap(err, "migration aborted")
+		}
+		if err := gORM.WithContext(ctx).AutoMigrate(t); err != nil {
+			return errors.Wrapf(err, "failed to migrate %s", typeName(t))
+		}
+	}
+	return nil
+}
+
+// typeName returns the name of the model's type, dereferencing pointers.
+func typeName(model interface{}) string {
+	t := reflect.TypeOf(model)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil {
+		return "<nil>"
+	}
+	return t.String()
+}
+
+// DropTables drops the tables of the given models if they exist.
+func DropTables(gORM *gorm.DB, types ...interface{}) error {
+	if err := gORM.Migrator().DropTable(types...); err != nil {
+		return errors.Wrap(err, "failed to drop tables")
+	}
+	return nil
+}
+
+// Migration is a versioned schema change identified by a unique ID.
+type Migration struct {
+	ID   string
+	Up   func(tx *gorm.DB) error
+	Down func(tx *gorm.DB) error
+}
+
+// schemaMigration records an applied migration together with the batch it was applied in.
+type schemaMigration struct {
+	ID        string `gorm:"primaryKey"`
+	Batch     int    `gorm:"not null;index"`
+	AppliedAt time.Time
+}
+
+// TableName returns the table that tracks applied migrations.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrator applies versioned migrations in order and records them in the schema_migrations table.
+// Every call to Up applies the pending migrations as one batch, which Rollback reverts as a unit.
+type Migrator struct {
+	DB         *gorm.DB
+	Migrations []Migration
+}
+
+// NewMigrator creates a new instance of Migrator for the given migrations, applied in the order provided.
+func NewMigrator(DB *gorm.DB, migrations ...Migration) *Migrator {
+	return &Migrator{DB: DB, Migrations: migrations}
+}
+
+// Up applies every pending migration in a single transaction and records them as a new batch.
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		applied, err := m.applied(tx)
+		if err != nil {
+			return err
+		}
+
+		var batch int
+		if err := tx.Model(&schemaMigration{}).Select("COALESCE(MAX(batch), 0)").Scan(&batch).Error; err != nil {
+			return errors.Wrap(err, "failed to read the last migration batch")
+		}
+		batch++
+
+		for _, migration := range m.Migrations {
+			if _, ok := applied[migration.ID]; ok {
+				continue
+			}
+			if err := migration.Up(tx); err != nil {
+				return errors.Wrapf(err, "failed to apply migration %s", migration.ID)
+			}
+			record := &schemaMigration{ID: migration.ID, Batch: batch, AppliedAt: time.Now().UTC()}
+			if err := tx.Create(record).Error; err != nil {
+				return errors.Wrapf(err, "failed to record migration %s", migration.ID)
+			}
+		}
+		return nil
+	})
+}
+
+// Rollback reverts every migration of the last applied batch in reverse order within a single transaction.
+func (m *Migrator) Rollback(ctx context.Context) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	return m.DB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		applied, err := m.applied(tx)
+		if err != nil {
+			return err
+		}
+
+		lastBatch := 0
+		for _, record := range applied {
+			lastBatch = max(lastBatch, record.Batch)
+		}
+		if lastBatch == 0 {
+			return nil
+		}
+
+		known := make(map[string]struct{}, len(m.Migrations))
+		for _, migration := range m.Migrations {
+			known[migration.ID] = struct{}{}
+		}
+		for id, record := range applied {
+			if _, ok := known[id]; !ok && record.Batch == lastBatch {
+				return errors.Errorf("migration %s of the last batch is not registered", id)
+			}
+		}
+
+		for i := len(m.Migrations) - 1; i >= 0; i-- {
+			migration := m.Migrations[i]
+			record, ok := applied[migration.ID]
+			if !ok || record.Batch != lastBatch {
+				continue
+			}
+			if migration.Down == nil {
+				return errors.Wrapf(ErrIrreversibleMigration, "id: %s", migration.ID)
+			}
+			if err := migration.Down(tx); err != nil {
+				return errors.Wrapf(err, "failed to roll back migration %s", migration.ID)
+			}
+			if err := tx.Delete(&record).Error; err != nil {
+				return errors.Wrapf(err, "failed to remove migration record %s", migration.ID)
+			}
+		}
+		return nil
+	})
+}
+
+// applied loads the recorded migrations keyed by ID, creating the tracking table on first use.
+func (m *Migrator) applied(tx *gorm.DB) (map[string]schemaMigration, error) {
+	if err := tx.AutoMigrate(&schemaMigration{}); err != nil {
+		return nil, errors.Wrap(err, "failed to create the migrations table")
+	}
+
+	var records []schemaMigration
+	if err := tx.Find(&records).Error; err != nil {
+		return nil, errors.Wrap(err, "failed to load applied migrations")
+	}
+
+	applied := make(map[string]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.ID] = record
+	}
+	return applied, nil
+}
+
+// validate ensures every migration has a unique, non-empty ID and an Up step.
+func (m *Migrator) validate() error {
+	seen := make(map[string]struct{}, len(m.Migrations))
+	for _, migration := range m.Migrations {
+		if migration.ID == "" {
+			return errors.New("migration ID is required")
+		}
+		if migration.Up == nil {
+			return errors.Errorf("migration %s has no Up step", migration.ID)
+		}
+		if _, ok := seen[migration.ID]; ok {
+			return errors.Errorf("duplicate migration ID %s", migration.ID)
+		}
+		seen[migration.ID] = struct{}{}
+	}
+	return nil
+}
@@ -2,371 +2,760 @@ package tests
 
 import (
 	"context"
+	"database/sql"
 	"database/sql/driver"
 	"fmt"
-	"strconv"
+	"io"
+	"os"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	postgrescontainer "github.com/NekKkMirror/go-app/internal/pkg/container/test/postgres"
+	"github.com/NekKkMirror/go-app/internal/pkg/logger/mocks"
 	"github.com/NekKkMirror/go-app/internal/pkg/orm-pgsql"
 	"github.com/NekKkMirror/go-app/internal/pkg/utils/db/pagination"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	"gorm.io/gorm"
 )
 
 func TestPaginateWithFilters(t *testing.T) {
 	ctx := context.Background()
-	DB, m, err := postgrescontainer.Start(ctx, t)
+
+	tests := []struct {
+		filter *pagination.FilterModel
+		where  string
+		args   []driver.Value
+	}{
+		{&pagination.FilterModel{Field: "age", Value: "30", Comparison: "="}, "age = $1", []driver.Value{"30"}},
+		{&pagination.FilterModel{Field: "name", Value: "Alice", Comparison: "starts_with"}, "name LIKE $1", []driver.Value{"Alice%"}},
+		{&pagination.FilterModel{Field: "total_spent", Value: "1000", Comparison: ">"}, "total_spent > $1", []driver.Value{"1000"}},
+		{&pagination.FilterModel{Field: "total_spent", Value: "500", Comparison: "<"}, "total_spent < $1", []driver.Value{"500"}},
+		{&pagination.FilterModel{Field: "name", Value: "John", Comparison: "!="}, "name <> $1", []driver.Value{"John"}},
+		{&pagination.FilterModel{Field: "age", Value: "25,35", Comparison: "between"}, "age BETWEEN $1 AND $2", []driver.Value{"25", "35"}},
+		{&pagination.FilterModel{Field: "name", Value: "Doe", Comparison: "ends_with"}, "name LIKE $1", []driver.Value{"%Doe"}},
+		{&pagination.FilterModel{Field: "email", Value: "example.com", Comparison: "ilike"}, "email ILIKE $1", []driver.Value{"example.com"}},
+		{&pagination.FilterModel{Field: "age", Value: "40", Comparison: "is_not_null"}, "age IS NOT NULL", nil},
+		{&pagination.FilterModel{Field: "total_spent", Value: "2000", Comparison: "not_in"}, "total_spent NOT IN ($1)", []driver.Value{"2000"}},
+		{&pagination.FilterModel{Field: "is_active", Comparison: "is_true"}, "is_active IS TRUE", nil},
+		{&pagination.FilterModel{Field: "is_active", Comparison: "is_false"}, "is_active IS FALSE", nil},
+		{&pagination.FilterModel{Field: "is_admin", Comparison: "is_not_false"}, "is_admin IS NOT FALSE", nil},
+		{&pagination.FilterModel{Field: "is_active", Comparison: "is_unknown"}, "is_active IS UNKNOWN", nil},
+		{&pagination.FilterModel{Field: "is_active", Comparison: "is_not_unknown"}, "is_active IS NOT UNKNOWN", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_positive"}, "age > 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_negative"}, "age < 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_not_positive"}, "age <= 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_not_negative"}, "age >= 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_even"}, "age % 2 = 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "0", Comparison: "is_odd"}, "age % 2 != 0", nil},
+		{&pagination.FilterModel{Field: "age", Value: "2", Comparison: "is_divisible_by"}, "age % $1 = 0", []driver.Value{2}},
+		{&pagination.FilterModel{Field: "is_admin", Comparison: "is_not_true"}, "is_admin IS NOT TRUE", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filter.Comparison, func(t *testing.T) {
+			DB, m, err := postgrescontainer.NewMock()
+			if err != nil {
+				t.Fatalf("expected no error from NewMock, got %v", err)
+			}
+
+			listQuery := &pagination.ListQuery{
+				Size:    10,
+				Page:    1,
+				OrderBy: "created_at DESC",
+				Filters: []*pagination.FilterModel{tt.filter},
+			}
+			testPaginationWithFilters(t, ctx, listQuery, DB, m, tt.where, tt.args)
+		})
+	}
+}
+
+func TestPaginateReturnsFirstPage(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
 	if err != nil {
 		t.Fatalf("expected no error from DB, got %v", err)
 	}
 
-	listQuery1 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "30", Comparison: "="},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery1, DB, m)
-
-	listQuery2 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "name", Value: "Alice", Comparison: "starts_with"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery2, DB, m)
-
-	listQuery3 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "total_spent", Value: "1000", Comparison: ">"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery3, DB, m)
-
-	listQuery4 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "total_spent", Value: "500", Comparison: "<"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery4, DB, m)
-
-	listQuery5 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "name", Value: "John", Comparison: "!="},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery5, DB, m)
-
-	listQuery6 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "25,35", Comparison: "between"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery6, DB, m)
-
-	listQuery7 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "name", Value: "Doe", Comparison: "ends_with"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery7, DB, m)
-
-	listQuery8 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "email", Value: "example.com", Comparison: "ilike"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery8, DB, m)
-
-	listQuery9 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "40", Comparison: "is_not_null"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery9, DB, m)
-
-	listQuery10 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "total_spent", Value: "2000", Comparison: "not_in"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery10, DB, m)
-
-	listQuery11 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_active", Comparison: "is_true"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery11, DB, m)
-
-	listQuery12 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_active", Comparison: "is_false"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery12, DB, m)
-
-	listQuery13 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_admin", Comparison: "is_not_false"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery13, DB, m)
-
-	listQuery14 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_active", Comparison: "is_unknown"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery14, DB, m)
-
-	listQuery15 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_active", Comparison: "is_not_unknown"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery15, DB, m)
-
-	listQuery16 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_positive"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery16, DB, m)
-
-	listQuery17 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_negative"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery17, DB, m)
-
-	listQuery18 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_not_positive"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery18, DB, m)
-
-	listQuery19 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_not_negative"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery19, DB, m)
-
-	listQuery20 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_even"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery20, DB, m)
-
-	listQuery21 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "0", Comparison: "is_odd"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery21, DB, m)
-
-	listQuery22 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "age", Value: "2", Comparison: "is_divisible_by"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery22, DB, m)
-
-	listQuery23 := &pagination.ListQuery{
-		Size:    10,
-		Page:    1,
-		OrderBy: "created_at DESC",
-		Filters: []*pagination.FilterModel{
-			{Field: "is_admin", Comparison: "is_not_true"},
-		},
-	}
-	testPaginationWithFilters(t, ctx, listQuery23, DB, m)
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, pagination.NewListQuery(10, 1), DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalCount != 40 {
+		t.Errorf("expected total count 40, got %d", result.TotalCount)
+	}
+	if len(result.Data) != 10 {
+		t.Errorf("expected 10 items on the first page, got %d", len(result.Data))
+	}
 }
 
-func testPaginationWithFilters(t *testing.T, ctx context.Context, listQuery *pagination.ListQuery, DB *gorm.DB, m sqlmock.Sqlmock) {
-	var expectedQuery string
-	var expectedArgs []driver.Value
-
-	for _, filter := range listQuery.Filters {
-		switch filter.Comparison {
-		case "is_divisible_by":
-			expectedQuery += fmt.Sprintf(" AND %s %s?", filter.Field, "mod")
-			expectedArgs = append(expectedArgs, filter.Value, 0)
-		case "is_not_divisible_by":
-		case "is_negative":
-			expectedQuery += fmt.Sprintf(" AND %s < 0", filter.Field)
-			expectedArgs = append(expectedArgs, 0)
-		case "is_not_negative":
-			expectedQuery += fmt.Sprintf(" AND %s >= 0", filter.Field)
-			expectedArgs = append(expectedArgs, 0)
-		case "is_positive":
-			expectedQuery += fmt.Sprintf(" AND %s > 0", filter.Field)
-			expectedArgs = append(expectedArgs, 0)
-		case "is_not_positive":
-			expectedQuery += fmt.Sprintf(" AND %s <= 0", filter.Field)
-			expectedArgs = append(expectedArgs, 0)
-		case "is_unknown":
-			expectedQuery += fmt.Sprintf(" AND %s IS NULL", filter.Field)
-			expectedArgs = append(expectedArgs, nil)
-		case "is_not_unknown":
-			expectedQuery += fmt.Sprintf(" AND %s IS NOT NULL", filter.Field)
-			expectedArgs = append(expectedArgs, nil)
-		case "is_false":
-			expectedQuery += fmt.Sprintf(" AND %s IS FALSE", filter.Field)
-			expectedArgs = append(expectedArgs, false)
-		case "is_not_false":
-			expectedQuery += fmt.Sprintf(" AND %s IS NOT FALSE", filter.Field)
-			expectedArgs = append(expectedArgs, false)
-		case "is_true":
-			expectedQuery += fmt.Sprintf(" AND %s IS TRUE", filter.Field)
-			expectedArgs = append(expectedArgs, true)
-		case "is_not_true":
-			expectedQuery += fmt.Sprintf(" AND %s IS NOT TRUE", filter.Field)
-			expectedArgs = append(expectedArgs, true)
-		case "is_even":
-			expectedQuery += fmt.Sprintf(" AND %s %s 0", filter.Field, "%")
-			expectedArgs = append(expectedArgs, 0)
-		case "is_odd":
-			expectedQuery += fmt.Sprintf(" AND %s %s 1", filter.Field, "%")
-			expectedArgs = append(expectedArgs, 1)
-		case "=":
-			expectedQuery += fmt.Sprintf(" AND %s = ?", filter.Field)
-			expectedArgs = append(expectedArgs, filter.Value)
-		case "!=":
-			expectedQuery += fmt.Sprintf(" AND %s != ?", filter.Field)
-			expectedArgs = append(expectedArgs, filter.Value)
-		case "starts_with":
-			expectedQuery += fmt.Sprintf(" AND %s LIKE ?", filter.Field)
-			expectedArgs = append(expectedArgs, filter.Value+"%")
-		case "ends_with":
-			expectedQuery += fmt.Sprintf(" AND %s LIKE ?", filter.Field)
-			expectedArgs = append(expectedArgs, "%"+filter.Value)
-		case "contains":
-		case ">":
-			expectedQuery += fmt.Sprintf(" AND %s > ?", filter.Field)
-			expectedArgs = append(expectedArgs, filter.Value)
-		case "<":
-			expectedQuery += fmt.Sprintf(" AND %s < ?", filter.Field)
-			expectedArgs = append(expectedArgs, filter.Value)
-		case "like":
-			expectedQuery += fmt.Sprintf(" AND %s LIKE ?", filter.Field)
-			expectedArgs = append(expectedArgs, "%"+filter.Value+"%")
-		case "ilike":
-			expectedQuery += fmt.Sprintf(" AND %s ILIKE ?", filter.Field)
-			expectedArgs = append(expectedArgs, "%"+filter.Value+"%")
-		case "is_null":
-			expectedQuery += fmt.Sprintf(" AND %s IS NULL", filter.Field)
-			expectedArgs = append(expectedArgs, nil)
-		case "is_not_null":
-			expectedQuery += fmt.Sprintf(" AND %s IS NOT NULL", filter.Field)
-			expectedArgs = append(expectedArgs, nil)
-		case "in":
-			queryArray := strings.Split(filter.Value, ",")
-			expectedQuery += fmt.Sprintf(" AND %s IN (?)", filter.Field)
-			expectedArgs = append(expectedArgs, queryArray)
-		case "not_in":
-			queryArray := strings.Split(filter.Value, ",")
-			expectedQuery += fmt.Sprintf(" AND %s NOT IN (?)", filter.Field)
-			expectedArgs = append(expectedArgs, queryArray)
-		case "between":
-			rangeArray := strings.Split(filter.Value, ",")
-			minValue, err := strconv.ParseFloat(rangeArray[0], 64)
-			if err != nil {
-				t.Errorf("failed to parse min value: %v", err)
-				return
-			}
-			maxValue, err := strconv.ParseFloat(rangeArray[1], 64)
-			if err != nil {
-				t.Errorf("failed to parse max value: %v", err)
-				return
-			}
-			expectedQuery += fmt.Sprintf(" AND %s BETWEEN ? AND ?", filter.Field)
-			expectedArgs = append(expectedArgs, minValue, maxValue)
-		default:
-			t.Errorf("unsupported filter comparison: %s", filter.Comparison)
-			return
+func TestPaginateCountsOnlyFilteredRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	listQuery := pagination.NewListQuery(2, 1)
+	listQuery.Filters = []*pagination.FilterModel{
+		{Field: "is_admin", Comparison: "is_true"},
+	}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FilteredCount != 4 {
+		t.Errorf("expected filtered count 4 for admins, got %d", result.FilteredCount)
+	}
+	if result.TotalCount != 40 {
+		t.Errorf("expected total count 40 for the whole table, got %d", result.TotalCount)
+	}
+	if result.TotalPages != 2 {
+		t.Errorf("expected 2 pages, got %d", result.TotalPages)
+	}
+	if !result.HasNextPage {
+		t.Error("expected the first page to have a next page")
+	}
+	if len(result.Data) != 2 {
+		t.Errorf("expected 2 items on the first page, got %d", len(result.Data))
+	}
+}
+
+func TestPaginateReturnsEmptyResultWhenNothingMatches(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.Filters = []*pagination.FilterModel{
+		{Field: "name", Value: "Nobody", Comparison: "="},
+	}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.FilteredCount != 0 || result.TotalCount != 40 || !result.IsEmpty || result.HasNextPage {
+		t.Errorf("expected an empty result, got %+v", result)
+	}
+}
+
+func TestPaginateReturnsFilterErrors(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.Filters = []*pagination.FilterModel{
+		{Field: "age", Value: "30", Comparison: "unknown"},
+	}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
+	if err == nil {
+		t.Fatal("expected an error for an unsupported comparison, got nil")
+	}
+	if result != nil {
+		t.Fatal("expected a nil result, got non-nil")
+	}
+}
+
+func TestPaginateSelectsRequestedColumns(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.Select = []string{"id", "name"}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data) != 10 {
+		t.Fatalf("expected 10 items on the first page, got %d", len(result.Data))
+	}
+	for _, user := range result.Data {
+		if user.ID == 0 || user.Name == "" {
+			t.Errorf("expected id and name to be fetched, got %+v", user)
+		}
+		if user.Email != "" || user.Age != 0 {
+			t.Errorf("expected unselected columns to be empty, got email %q and age %d", user.Email, user.Age)
+		}
+	}
+}
+
+func TestPaginateWithoutSelectFetchesAllColumns(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, pagination.NewListQuery(10, 1), DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, user := range result.Data {
+		if user.Email == "" {
+			t.Errorf("expected email to be fetched, got %+v", user)
+		}
+	}
+}
+
+func TestPaginateRejectsUnknownSelectColumn(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.Select = []string{"id", "password_hash"}
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
+	if err == nil {
+		t.Fatal("expected an error for a column outside the model, got nil")
+	}
+	if result != nil {
+		t.Fatal("expected a nil result, got non-nil")
+	}
+}
+
+type author struct {
+	ID    int
+	Name  string
+	Books []book
+}
+
+type book struct {
+	ID       int
+	Title    string
+	AuthorID int
+	Author   *author
+}
+
+func seedAuthors(t *testing.T, DB *gorm.DB) {
+	if err := DB.AutoMigrate(&author{}, &book{}); err != nil {
+		t.Fatalf("failed to migrate authors: %v", err)
+	}
+	authors := []author{
+		{ID: 1, Name: "Ursula", Books: []book{{ID: 1, Title: "The Dispossessed"}, {ID: 2, Title: "The Lathe of Heaven"}}},
+		{ID: 2, Name: "Stanislaw", Books: []book{{ID: 3, Title: "Solaris"}}},
+	}
+	if err := DB.Create(&authors).Error; err != nil {
+		t.Fatalf("failed to seed authors: %v", err)
+	}
+}
+
+func TestPaginatePreloadsAssociations(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	seedAuthors(t, DB)
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.OrderBy = "id"
+	listQuery.Preloads = []string{"Books"}
+
+	result, err := ormpgsql.Paginate[author](ctx, listQuery, DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data) != 2 {
+		t.Fatalf("expected 2 authors, got %d", len(result.Data))
+	}
+	if got := len(result.Data[0].Books); got != 2 {
+		t.Errorf("expected 2 books for the first author, got %d", got)
+	}
+	if got := len(result.Data[1].Books); got != 1 {
+		t.Errorf("expected 1 book for the second author, got %d", got)
+	}
+}
+
+func TestPaginateJoinsAssociations(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	seedAuthors(t, DB)
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.OrderBy = "books.id"
+	listQuery.Joins = []string{"Author"}
+
+	result, err := ormpgsql.Paginate[book](ctx, listQuery, DB)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Data) != 3 {
+		t.Fatalf("expected 3 books, got %d", len(result.Data))
+	}
+	if result.Data[0].Author == nil || result.Data[0].Author.Name != "Ursula" {
+		t.Errorf("expected the first book to be joined with its author, got %+v", result.Data[0].Author)
+	}
+}
+
+func TestPaginateRejectsUnknownAssociation(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	seedAuthors(t, DB)
+
+	listQuery := pagination.NewListQuery(10, 1)
+	listQuery.Preloads = []string{"Publisher"}
+
+	if _, err := ormpgsql.Paginate[author](ctx, listQuery, DB); err == nil {
+		t.Fatal("expected an error for an undeclared association, got nil")
+	}
+}
+
+func TestPaginateReturnsErrorForCancelledContext(t *testing.T) {
+	DB, err := postgrescontainer.Start(context.Background(), t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, pagination.NewListQuery(10, 1), DB)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if result != nil {
+		t.Fatal("expected a nil result, got non-nil")
+	}
+}
+
+func TestNewORMSucceedsWhenDatabaseAlreadyExists(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		DB, err := ormpgsql.NewORM(cfg)
+		if err != nil {
+			t.Fatalf("expected NewORM call %d to succeed, got %v", i+1, err)
+		}
+		if DB == nil {
+			t.Fatalf("expected a valid DB instance from NewORM call %d, got nil", i+1)
+		}
+	}
+}
+
+func TestNewReturnsORMThatCanBeClosed(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	if orm.Config() != cfg {
+		t.Error("expected the ORM to keep the configuration it was created with")
+	}
+
+	if err := orm.DB.Exec("SELECT 1").Error; err != nil {
+		t.Fatalf("expected query to succeed before Close, got %v", err)
+	}
+
+	if err := orm.Close(); err != nil {
+		t.Fatalf("expected no error from Close, got %v", err)
+	}
+
+	if err := orm.DB.Exec("SELECT 1").Error; err == nil {
+		t.Fatal("expected query to fail after Close, got nil")
+	}
+}
+
+func TestPingSucceedsAgainstOpenDatabase(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer orm.Close()
+
+	if err := orm.Ping(ctx); err != nil {
+		t.Fatalf("expected Ping to succeed, got %v", err)
+	}
+	if stats := orm.Stats(); stats.OpenConnections < 1 {
+		t.Errorf("expected at least one open connection, got %d", stats.OpenConnections)
+	}
+}
+
+func TestPingFailsAgainstClosedDatabase(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	if err := orm.Close(); err != nil {
+		t.Fatalf("expected no error from Close, got %v", err)
+	}
+
+	if err := orm.Ping(ctx); err == nil {
+		t.Fatal("expected Ping to fail after Close, got nil")
+	}
+}
+
+func TestNewTracesQueries(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previous)
+
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer orm.Close()
+
+	exporter.Reset()
+	var users []postgrescontainer.User
+	if err := orm.DB.WithContext(ctx).Find(&users).Error; err != nil {
+		t.Fatalf("expected no error from Find, got %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].Name != "gorm.query" {
+		t.Fatalf("expected a single gorm.query span, got %v", spans)
+	}
+	attributes := make(map[attribute.Key]attribute.Value)
+	for _, kv := range spans[0].Attributes {
+		attributes[kv.Key] = kv.Value
+	}
+	if statement := attributes["db.statement"].AsString(); !strings.HasPrefix(statement, "SELECT") {
+		t.Errorf("expected db.statement to hold the query, got %q", statement)
+	}
+	if rows := attributes["db.rows_affected"].AsInt64(); rows != int64(len(users)) {
+		t.Errorf("expected db.rows_affected to be %d, got %d", len(users), rows)
+	}
+}
+
+func TestNewWithLoggerWarnsAboutSlowQueries(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	slowCfg := *cfg
+	slowCfg.SlowThreshold = 100 * time.Millisecond
+
+	log := mocks.NewILogger(t)
+	log.On("Warnf", "slow query over %s [%.3fms] [rows:%d] %s",
+		slowCfg.SlowThreshold, mock.Anything, mock.Anything,
+		mock.MatchedBy(func(sql string) bool { return strings.Contains(sql, "pg_sleep") }),
+	).Return().Once()
+
+	orm, err := ormpgsql.NewWithLogger(&slowCfg, log)
+	if err != nil {
+		t.Fatalf("expected no error from NewWithLogger, got %v", err)
+	}
+	defer orm.Close()
+
+	if err := orm.DB.WithContext(ctx).Exec("SELECT pg_sleep(0.3)").Error; err != nil {
+		t.Fatalf("expected slow query to succeed, got %v", err)
+	}
+	if err := orm.DB.WithContext(ctx).Exec("SELECT 1").Error; err != nil {
+		t.Fatalf("expected fast query to succeed, got %v", err)
+	}
+}
+
+func TestNewORMWithDBWarnsAboutSlowQueriesWithoutLogger(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("expected no error from sqlmock, got %v", err)
+	}
+	defer sqlDB.Close()
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_sleep(0.3)")).
+		WillDelayFor(300 * time.Millisecond).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// gorm's default logger writes to the standard output captured when the database is opened.
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("expected no error from os.Pipe, got %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	DB, err := ormpgsql.NewORMWithDB(sqlDB, &ormpgsql.PostgresConfig{SlowThreshold: 100 * time.Millisecond})
+	os.Stdout = stdout
+	if err != nil {
+		t.Fatalf("expected no error from NewORMWithDB, got %v", err)
+	}
+
+	if err := DB.Exec("SELECT pg_sleep(0.3)").Error; err != nil {
+		t.Fatalf("expected slow query to succeed, got %v", err)
+	}
+	writer.Close()
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("failed to read the logger output: %v", err)
+	}
+	if !strings.Contains(string(output), "SLOW SQL >= 100ms") {
+		t.Errorf("expected a slow query warning for the 100ms threshold, got %q", output)
+	}
+}
+
+func TestNewReusesPreparedStatementsWhenEnabled(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	preparedCfg := *cfg
+	preparedCfg.PrepareStmt = true
+
+	orm, err := ormpgsql.New(&preparedCfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer orm.Close()
+
+	preparedDB, ok := orm.DB.ConnPool.(*gorm.PreparedStmtDB)
+	if !ok {
+		t.Fatalf("expected a prepared statement pool, got %T", orm.DB.ConnPool)
+	}
+	before := len(preparedDB.Stmts)
+
+	for _, id := range []int{1, 2, 3} {
+		var user postgrescontainer.User
+		if err := orm.DB.WithContext(ctx).First(&user, "id = ?", id).Error; err != nil {
+			t.Fatalf("expected user %d to be found, got %v", id, err)
 		}
 	}
 
-	m.ExpectQuery("SELECT * FROM user WHERE 1=1" + expectedQuery).
-		WithArgs(expectedArgs...).
+	if cached := len(preparedDB.Stmts) - before; cached != 1 {
+		t.Errorf("expected identical queries to share 1 prepared statement, got %d", cached)
+	}
+}
+
+func TestNewDoesNotPrepareStatementsByDefault(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer orm.Close()
+
+	if _, ok := orm.DB.ConnPool.(*gorm.PreparedStmtDB); ok {
+		t.Error("expected statements not to be prepared unless PrepareStmt is set")
+	}
+}
+
+func TestNewAppliesConnectionPoolSettings(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	poolCfg := *cfg
+	poolCfg.MaxOpenConns = 7
+	poolCfg.MaxIdleConns = 3
+	poolCfg.ConnMaxLifetime = time.Hour
+	poolCfg.ConnMaxIdleTime = 100 * time.Millisecond
+
+	orm, err := ormpgsql.New(&poolCfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer orm.Close()
+
+	if got := orm.Stats().MaxOpenConnections; got != poolCfg.MaxOpenConns {
+		t.Errorf("expected max open connections %d, got %d", poolCfg.MaxOpenConns, got)
+	}
+
+	// Releasing five connections keeps three idle and closes the other two, then the idle ones time out.
+	releaseConnections(t, ctx, orm, 5)
+	if closed := orm.Stats().MaxIdleClosed; closed != 2 {
+		t.Errorf("expected 2 connections beyond MaxIdleConns to be closed, got %d", closed)
+	}
+	waitForStats(t, orm, func(stats sql.DBStats) bool { return stats.Idle == 0 && stats.MaxIdleTimeClosed >= 3 })
+	if closed := orm.Stats().MaxLifetimeClosed; closed != 0 {
+		t.Errorf("expected no connection to reach its one hour lifetime, got %d closed", closed)
+	}
+
+	lifetimeCfg := *cfg
+	lifetimeCfg.ConnMaxLifetime = 100 * time.Millisecond
+
+	shortLived, err := ormpgsql.New(&lifetimeCfg)
+	if err != nil {
+		t.Fatalf("expected no error from New, got %v", err)
+	}
+	defer shortLived.Close()
+
+	releaseConnections(t, ctx, shortLived, 1)
+	waitForStats(t, shortLived, func(stats sql.DBStats) bool { return stats.Idle == 0 && stats.MaxLifetimeClosed >= 1 })
+}
+
+// releaseConnections opens count connections at once and returns them all to the pool.
+func releaseConnections(t *testing.T, ctx context.Context, orm *ormpgsql.ORM, count int) {
+	t.Helper()
+	sqlDB, err := orm.DB.DB()
+	if err != nil {
+		t.Fatalf("expected no error retrieving *sql.DB, got %v", err)
+	}
+
+	conns := make([]*sql.Conn, 0, count)
+	for range count {
+		conn, err := sqlDB.Conn(ctx)
+		if err != nil {
+			t.Fatalf("failed to open a connection: %v", err)
+		}
+		conns = append(conns, conn)
+	}
+	for _, conn := range conns {
+		if err := conn.Close(); err != nil {
+			t.Fatalf("failed to release a connection: %v", err)
+		}
+	}
+}
+
+// waitForStats polls the pool statistics of orm until done reports true. The pool closes expired
+// connections from a background cleaner that runs at most once per second.
+func waitForStats(t *testing.T, orm *ormpgsql.ORM, done func(sql.DBStats) bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !done(orm.Stats()) {
+		if time.Now().After(deadline) {
+			t.Fatalf("pool statistics did not reach the expected state, got %+v", orm.Stats())
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+func TestNewORMAcceptsValidDBName(t *testing.T) {
+	ctx := context.Background()
+	_, cfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	validCfg := *cfg
+	validCfg.DBName = "valid_db_1"
+
+	DB, err := ormpgsql.NewORM(&validCfg)
+	if err != nil {
+		t.Fatalf("expected no error for a valid database name, got %v", err)
+	}
+	if DB == nil {
+		t.Fatal("expected a valid DB instance, got nil")
+	}
+}
+
+func TestNewStopsRetryingAfterConfiguredAttempts(t *testing.T) {
+	cfg := &ormpgsql.PostgresConfig{
+		Host:                "127.0.0.1",
+		Port:                1,
+		User:                "postgres",
+		Password:            "postgres",
+		DBName:              "unreachable_db",
+		RetryMaxAttempts:    2,
+		RetryMaxElapsedTime: 30 * time.Second,
+	}
+
+	orm, err := ormpgsql.New(cfg)
+	if err == nil {
+		t.Fatal("expected an error for an unreachable database, got nil")
+	}
+	if orm != nil {
+		t.Fatal("expected a nil ORM instance, got non-nil")
+	}
+	if !strings.Contains(err.Error(), "giving up after 2 attempts") {
+		t.Errorf("expected retries to stop after 2 attempts, got %v", err)
+	}
+}
+
+func TestNewORMRejectsDBNameWithSemicolon(t *testing.T) {
+	cfg := &ormpgsql.PostgresConfig{
+		Host:     "localhost",
+		Port:     5432,
+		User:     "postgres",
+		Password: "postgres",
+		DBName:   "test_db; DROP DATABASE postgres",
+	}
+
+	DB, err := ormpgsql.NewORM(cfg)
+	if !errors.Is(err, ormpgsql.ErrInvalidDBName) {
+		t.Fatalf("expected ErrInvalidDBName, got %v", err)
+	}
+	if DB != nil {
+		t.Fatal("expected a nil DB instance, got non-nil")
+	}
+}
+
+// testPaginationWithFilters expects the filtered count, the total count and the page query that Paginate
+// issues for listQuery, whose filters must produce the given WHERE clause and arguments.
+func testPaginationWithFilters(t *testing.T, ctx context.Context, listQuery *pagination.ListQuery, DB *gorm.DB, m sqlmock.Sqlmock, where string, args []driver.Value) {
+	t.Helper()
+
+	m.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "users" WHERE `+where) + "$").
+		WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	m.ExpectQuery("^" + regexp.QuoteMeta(`SELECT count(*) FROM "users"`) + "$").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(40))
+	m.ExpectQuery("^" + regexp.QuoteMeta(fmt.Sprintf(`SELECT * FROM "users" WHERE %s ORDER BY created_at DESC LIMIT $%d`, where, len(args)+1)) + "$").
+		WithArgs(append(args, listQuery.GetLimit())...).
 		WillReturnRows(sqlmock.NewRows([]string{"age"}).AddRow(30))
 
-	result, err := ormpgsql.Paginate[postgrescontainer.User](listQuery, DB)
+	result, err := ormpgsql.Paginate[postgrescontainer.User](ctx, listQuery, DB)
 	if err != nil {
-		t.Errorf("unexpected error: %v", err)
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.TotalCount != 40 || result.FilteredCount != 1 || len(result.Data) != 1 {
+		t.Errorf("expected 1 of 40 users, got %d of %d with %d rows", result.FilteredCount, result.TotalCount, len(result.Data))
 	}
-	if result == nil {
-		t.Error("pagination result is nil")
+	if err := m.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
 	}
 }
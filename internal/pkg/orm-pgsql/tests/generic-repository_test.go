@@ -0,0 +1,1440 @@
+package tests
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	postgrescontainer "github.com/NekKkMirror/go-app/internal/pkg/container/test/postgres"
+	"github.com/NekKkMirror/go-app/internal/pkg/orm-pgsql"
+	"github.com/NekKkMirror/go-app/internal/pkg/utils/db/pagination"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+func TestTransactionCommitsRepositoryOperations(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+
+	err = ormpgsql.Transaction(ctx, DB, func(tx *gorm.DB) error {
+		txRepo := repo.WithTx(tx)
+
+		user := &postgrescontainer.User{ID: 100, Name: "Created In Tx"}
+		if err := txRepo.Create(ctx, user); err != nil {
+			return err
+		}
+
+		user.Name = "Updated In Tx"
+		return txRepo.Update(ctx, user)
+	})
+	if err != nil {
+		t.Fatalf("expected transaction to commit, got %v", err)
+	}
+
+	user, err := repo.GetById(ctx, "100")
+	if err != nil {
+		t.Fatalf("expected committed user to be found, got %v", err)
+	}
+	if user.Name != "Updated In Tx" {
+		t.Errorf("expected name %q, got %q", "Updated In Tx", user.Name)
+	}
+}
+
+func TestTransactionRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	errAbort := errors.New("abort")
+
+	err = ormpgsql.Transaction(ctx, DB, func(tx *gorm.DB) error {
+		if err := repo.WithTx(tx).Create(ctx, &postgrescontainer.User{ID: 101, Name: "Rolled Back"}); err != nil {
+			return err
+		}
+		return errAbort
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("expected the transaction error to be returned, got %v", err)
+	}
+
+	if _, err := repo.GetById(ctx, "101"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("expected rolled back user to be missing, got %v", err)
+	}
+}
+
+func TestReadMethodsExcludeSoftDeletedRows(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	// The seed soft deletes every tenth user, leaving 36 of 40 active.
+	const activeUsers = 36
+	const activeBasicUsers = 35
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	basic := &postgrescontainer.User{SubscriptionType: "Basic"}
+
+	if _, err := repo.GetById(ctx, "10"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("GetById: expected soft-deleted user to be missing, got %v", err)
+	}
+
+	if _, err := repo.Get(ctx, &postgrescontainer.User{ID: 10}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("Get: expected soft-deleted user to be missing, got %v", err)
+	}
+
+	all, err := repo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("GetAll: unexpected error: %v", err)
+	}
+	if len(*all) != activeUsers {
+		t.Errorf("GetAll: expected %d users, got %d", activeUsers, len(*all))
+	}
+
+	where, err := repo.Where(ctx, basic)
+	if err != nil {
+		t.Fatalf("Where: unexpected error: %v", err)
+	}
+	if len(*where) != activeBasicUsers {
+		t.Errorf("Where: expected %d users, got %d", activeBasicUsers, len(*where))
+	}
+
+	page, err := repo.SkipTake(ctx, 0, 100)
+	if err != nil {
+		t.Fatalf("SkipTake: unexpected error: %v", err)
+	}
+	if len(*page) != activeUsers {
+		t.Errorf("SkipTake: expected %d users, got %d", activeUsers, len(*page))
+	}
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count: unexpected error: %v", err)
+	}
+	if count != activeUsers {
+		t.Errorf("Count: expected %d, got %d", activeUsers, count)
+	}
+
+	countWhere, err := repo.CountWhere(ctx, basic)
+	if err != nil {
+		t.Fatalf("CountWhere: unexpected error: %v", err)
+	}
+	if countWhere != activeBasicUsers {
+		t.Errorf("CountWhere: expected %d, got %d", activeBasicUsers, countWhere)
+	}
+}
+
+// orderLine is a model with a serial ID, a creation timestamp and a column computed by the database.
+type orderLine struct {
+	ID        int
+	Quantity  int
+	UnitPrice int
+	Total     int `gorm:"->;type:integer GENERATED ALWAYS AS (quantity * unit_price) STORED"`
+	CreatedAt time.Time
+}
+
+func TestCreatePopulatesGeneratedColumns(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&orderLine{}); err != nil {
+		t.Fatalf("failed to migrate order line: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[orderLine](DB)
+
+	line := &orderLine{Quantity: 3, UnitPrice: 5}
+	if err := repo.Create(ctx, line); err != nil {
+		t.Fatalf("expected create to succeed, got %v", err)
+	}
+	if line.ID == 0 || line.CreatedAt.IsZero() {
+		t.Errorf("expected ID and CreatedAt to be set, got %+v", line)
+	}
+	if line.Total != 15 {
+		t.Errorf("expected generated total 15, got %d", line.Total)
+	}
+
+	lines := []orderLine{{Quantity: 2, UnitPrice: 2}, {Quantity: 1, UnitPrice: 7}}
+	if err := repo.CreateMany(ctx, &lines); err != nil {
+		t.Fatalf("expected create many to succeed, got %v", err)
+	}
+	for i, want := range []int{4, 7} {
+		if lines[i].ID == 0 || lines[i].Total != want {
+			t.Errorf("expected line %d to have an ID and total %d, got %+v", i, want, lines[i])
+		}
+	}
+}
+
+// product is a model without a deleted_at column.
+type product struct {
+	ID   int
+	Name string
+}
+
+func TestGetByIdAgainstSQLMock(t *testing.T) {
+	ctx := context.Background()
+	DB, mock, err := postgrescontainer.NewMock()
+	if err != nil {
+		t.Fatalf("expected no error from NewMock, got %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE id = $1 ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("7", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Monitor"))
+
+	repo := ormpgsql.NewGenericRepository[product](DB)
+	found, err := repo.GetById(ctx, "7")
+	if err != nil {
+		t.Fatalf("expected product to be found, got %v", err)
+	}
+	if found.ID != 7 || found.Name != "Monitor" {
+		t.Errorf("expected product 7 named Monitor, got %+v", found)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestMissingRecordsReturnErrNotFound(t *testing.T) {
+	ctx := context.Background()
+	DB, mock, err := postgrescontainer.NewMock()
+	if err != nil {
+		t.Fatalf("expected no error from NewMock, got %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE id = $1 ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("404", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE "products"."name" = $1 ORDER BY "products"."id" LIMIT $2`)).
+		WithArgs("Missing", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+
+	repo := ormpgsql.NewGenericRepository[product](DB)
+
+	_, err = repo.GetById(ctx, "404")
+	if !errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected GetById to return ErrNotFound, got %v", err)
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected GetById to keep gorm.ErrRecordNotFound in the chain, got %v", err)
+	}
+
+	if _, err := repo.Get(ctx, &product{Name: "Missing"}); !errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected Get to return ErrNotFound, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestQueryErrorsAreNotReportedAsNotFound(t *testing.T) {
+	ctx := context.Background()
+	DB, mock, err := postgrescontainer.NewMock()
+	if err != nil {
+		t.Fatalf("expected no error from NewMock, got %v", err)
+	}
+
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products"`)).
+		WillReturnError(errors.New("connection reset"))
+
+	repo := ormpgsql.NewGenericRepository[product](DB)
+	if _, err := repo.GetById(ctx, "1"); err == nil || errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected a non-not-found error, got %v", err)
+	}
+}
+
+// stampedItem is a model whose timestamps gorm fills in on create and update.
+type stampedItem struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// utcTime matches time.Time arguments in the UTC location.
+type utcTime struct{}
+
+func (utcTime) Match(v driver.Value) bool {
+	ts, ok := v.(time.Time)
+	return ok && !ts.IsZero() && ts.Location() == time.UTC
+}
+
+func TestUTCTimestampsAreSetOnCreateAndUpdate(t *testing.T) {
+	ctx := context.Background()
+	sqlDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("failed to create sqlmock: %v", err)
+	}
+	DB, err := ormpgsql.NewORMWithDB(sqlDB, &ormpgsql.PostgresConfig{UTCTimestamps: true})
+	if err != nil {
+		t.Fatalf("expected no error from NewORMWithDB, got %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO "stamped_items" ("name","created_at","updated_at","id") VALUES ($1,$2,$3,$4) RETURNING *`)).
+		WithArgs("draft", utcTime{}, utcTime{}, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(`UPDATE "stamped_items" SET "name"=$1,"created_at"=$2,"updated_at"=$3 WHERE "id" = $4`)).
+		WithArgs("published", utcTime{}, utcTime{}, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	repo := ormpgsql.NewGenericRepository[stampedItem](DB)
+	item := &stampedItem{ID: 1, Name: "draft"}
+	if err := repo.Create(ctx, item); err != nil {
+		t.Fatalf("expected create to succeed, got %v", err)
+	}
+
+	item.Name = "published"
+	if err := repo.Update(ctx, item); err != nil {
+		t.Fatalf("expected update to succeed, got %v", err)
+	}
+	if item.UpdatedAt.Location() != time.UTC {
+		t.Errorf("expected UpdatedAt in UTC, got %v", item.UpdatedAt.Location())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestGetByIdForUpdateLocksRowInTransaction(t *testing.T) {
+	ctx := context.Background()
+	DB, mock, err := postgrescontainer.NewMock()
+	if err != nil {
+		t.Fatalf("expected no error from NewMock, got %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT * FROM "products" WHERE id = $1 ORDER BY "products"."id" LIMIT $2 FOR UPDATE`)).
+		WithArgs("7", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(7, "Monitor"))
+	mock.ExpectCommit()
+
+	repo := ormpgsql.NewGenericRepository[product](DB)
+	err = ormpgsql.Transaction(ctx, DB, func(tx *gorm.DB) error {
+		locked, err := repo.WithTx(tx).GetByIdForUpdate(ctx, "7")
+		if err != nil {
+			return err
+		}
+		if locked.Name != "Monitor" {
+			t.Errorf("expected product named Monitor, got %q", locked.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected transaction to succeed, got %v", err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("unmet sqlmock expectations: %v", err)
+	}
+}
+
+func TestDeleteRemovesRowsForModelsWithoutDeletedAt(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&product{}); err != nil {
+		t.Fatalf("failed to migrate product: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[product](DB)
+	if err := repo.Create(ctx, &product{ID: 1, Name: "Keyboard"}); err != nil {
+		t.Fatalf("failed to create product: %v", err)
+	}
+
+	found, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected product to be found, got %v", err)
+	}
+	if found.Name != "Keyboard" {
+		t.Errorf("expected name %q, got %q", "Keyboard", found.Name)
+	}
+
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatalf("expected hard delete to succeed, got %v", err)
+	}
+
+	var remaining int64
+	if err := DB.Model(&product{}).Where("id = ?", 1).Count(&remaining).Error; err != nil {
+		t.Fatalf("failed to count products: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("expected product row to be removed, got %d rows", remaining)
+	}
+}
+
+func TestDeleteSoftDeletesModelsWithDeletedAt(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatalf("expected soft delete to succeed, got %v", err)
+	}
+
+	if _, err := repo.GetById(ctx, "1"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected soft-deleted user to be hidden, got %v", err)
+	}
+
+	var remaining int64
+	if err := DB.Model(&postgrescontainer.User{}).Where("id = ?", 1).Count(&remaining).Error; err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	if remaining != 1 {
+		t.Errorf("expected soft-deleted row to remain, got %d rows", remaining)
+	}
+}
+
+func TestDeleteReturnsErrNotFoundForSoftDeletedRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	var before postgrescontainer.User
+	if err := DB.First(&before, 10).Error; err != nil {
+		t.Fatalf("failed to load user 10: %v", err)
+	}
+
+	// The seed soft deletes user 10.
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.Delete(ctx, "10"); !errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a soft-deleted user, got %v", err)
+	}
+	if err := repo.Delete(ctx, "999"); !errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a missing user, got %v", err)
+	}
+
+	var after postgrescontainer.User
+	if err := DB.First(&after, 10).Error; err != nil {
+		t.Fatalf("failed to reload user 10: %v", err)
+	}
+	if *after.DeletedAt != *before.DeletedAt {
+		t.Errorf("expected deleted_at to stay %s, got %s", *before.DeletedAt, *after.DeletedAt)
+	}
+}
+
+func TestPatchChangesOnlyTheGivenColumns(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	before, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected user to be found, got %v", err)
+	}
+
+	if err := repo.Patch(ctx, "1", map[string]any{"age": 0}); err != nil {
+		t.Fatalf("expected patch to succeed, got %v", err)
+	}
+
+	after, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected user to be found, got %v", err)
+	}
+	if after.Age != 0 {
+		t.Errorf("expected age to be patched to 0, got %d", after.Age)
+	}
+	after.Age = before.Age
+	if *after != *before {
+		t.Errorf("expected other columns to be unchanged, got %+v, want %+v", after, before)
+	}
+}
+
+func TestPatchRejectsUnknownColumnsAndMissingRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.Patch(ctx, "1", map[string]any{"password_hash": "x"}); err == nil {
+		t.Error("expected an error for a column outside the model, got nil")
+	}
+	if err := repo.Patch(ctx, "1000", map[string]any{"age": 30}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+}
+
+func TestPatchReturnsErrNotFoundForSoftDeletedRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	// The seed soft deletes user 10.
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.Patch(ctx, "10", map[string]any{"age": 99}); !errors.Is(err, ormpgsql.ErrNotFound) {
+		t.Errorf("expected ErrNotFound for a soft-deleted user, got %v", err)
+	}
+
+	var deleted postgrescontainer.User
+	if err := DB.First(&deleted, 10).Error; err != nil {
+		t.Fatalf("expected soft-deleted user to still exist, got %v", err)
+	}
+	if deleted.Age == 99 {
+		t.Error("expected the soft-deleted user to be left unchanged")
+	}
+}
+
+func TestDeleteManySoftDeletesEveryID(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.DeleteMany(ctx, []string{"1", "2", "3"}); err != nil {
+		t.Fatalf("expected soft delete to succeed, got %v", err)
+	}
+
+	for _, id := range []string{"1", "2", "3"} {
+		if _, err := repo.GetById(ctx, id); !errors.Is(err, gorm.ErrRecordNotFound) {
+			t.Errorf("expected soft-deleted user %s to be hidden, got %v", id, err)
+		}
+	}
+	if _, err := repo.GetById(ctx, "4"); err != nil {
+		t.Errorf("expected user 4 to be untouched, got %v", err)
+	}
+
+	var remaining int64
+	if err := DB.Model(&postgrescontainer.User{}).Where("id IN ?", []int{1, 2, 3}).Count(&remaining).Error; err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	if remaining != 3 {
+		t.Errorf("expected soft-deleted rows to remain, got %d rows", remaining)
+	}
+}
+
+func TestDeleteManyLeavesSoftDeletedRecordsUntouched(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	var before postgrescontainer.User
+	if err := DB.First(&before, 10).Error; err != nil {
+		t.Fatalf("failed to load user 10: %v", err)
+	}
+
+	// The seed soft deletes user 10.
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.DeleteMany(ctx, []string{"9", "10"}); err != nil {
+		t.Fatalf("expected soft delete to succeed, got %v", err)
+	}
+
+	if _, err := repo.GetById(ctx, "9"); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected soft-deleted user 9 to be hidden, got %v", err)
+	}
+	var after postgrescontainer.User
+	if err := DB.First(&after, 10).Error; err != nil {
+		t.Fatalf("failed to reload user 10: %v", err)
+	}
+	if *after.DeletedAt != *before.DeletedAt {
+		t.Errorf("expected deleted_at to stay %s, got %s", *before.DeletedAt, *after.DeletedAt)
+	}
+}
+
+func TestHardDeleteRemovesRowEntirely(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.HardDelete(ctx, "1"); err != nil {
+		t.Fatalf("expected hard delete to succeed, got %v", err)
+	}
+
+	var remaining int64
+	if err := DB.Model(&postgrescontainer.User{}).Where("id = ?", 1).Count(&remaining).Error; err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("expected user row to be removed, got %d rows", remaining)
+	}
+}
+
+func TestHardDeleteWhereRemovesMatchingRows(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.HardDeleteWhere(ctx, &postgrescontainer.User{ID: 2}); err != nil {
+		t.Fatalf("expected hard delete to succeed, got %v", err)
+	}
+
+	var remaining int64
+	if err := DB.Model(&postgrescontainer.User{}).Where("id = ?", 2).Count(&remaining).Error; err != nil {
+		t.Fatalf("failed to count users: %v", err)
+	}
+	if remaining != 0 {
+		t.Errorf("expected user row to be removed, got %d rows", remaining)
+	}
+
+	if err := repo.HardDeleteWhere(ctx, &postgrescontainer.User{}); !errors.Is(err, gorm.ErrMissingWhereClause) {
+		t.Errorf("expected empty parameters to be rejected, got %v", err)
+	}
+}
+
+func TestCountDistinctCountsUniqueValues(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+
+	count, err := repo.CountDistinct(ctx, "subscription_type")
+	if err != nil {
+		t.Fatalf("expected no error from CountDistinct, got %v", err)
+	}
+	if count != 2 {
+		t.Errorf("expected 2 distinct subscription types, got %d", count)
+	}
+
+	if _, err := repo.CountDistinct(ctx, "id); DROP TABLE users; --"); err == nil {
+		t.Error("expected an error for a column outside the model, got nil")
+	}
+}
+
+func TestExistsReportsMatchingRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+
+	exists, err := repo.Exists(ctx, &postgrescontainer.User{Name: "Alice Smith"})
+	if err != nil {
+		t.Fatalf("expected no error from Exists, got %v", err)
+	}
+	if !exists {
+		t.Error("expected a user named Alice Smith to exist")
+	}
+
+	exists, err = repo.Exists(ctx, &postgrescontainer.User{Name: "Nobody"})
+	if err != nil {
+		t.Fatalf("expected no error from Exists, got %v", err)
+	}
+	if exists {
+		t.Error("expected no user named Nobody to exist")
+	}
+}
+
+func TestExistsByIdIgnoresMissingAndSoftDeletedRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+
+	tests := []struct {
+		id   string
+		want bool
+	}{
+		{id: "1", want: true},
+		{id: "10", want: false},
+		{id: "1000", want: false},
+	}
+	for _, tt := range tests {
+		exists, err := repo.ExistsById(ctx, tt.id)
+		if err != nil {
+			t.Fatalf("expected no error from ExistsById(%s), got %v", tt.id, err)
+		}
+		if exists != tt.want {
+			t.Errorf("ExistsById(%s) = %v, want %v", tt.id, exists, tt.want)
+		}
+	}
+}
+
+// membership is a join-table model keyed by two columns.
+type membership struct {
+	UserID  int `gorm:"primaryKey"`
+	GroupID int `gorm:"primaryKey"`
+	Role    string
+}
+
+func seedMemberships(t *testing.T, ctx context.Context, DB *gorm.DB) *ormpgsql.GenericRepository[membership] {
+	if err := DB.AutoMigrate(&membership{}); err != nil {
+		t.Fatalf("failed to migrate membership: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[membership](DB)
+	memberships := []membership{
+		{UserID: 1, GroupID: 1, Role: "owner"},
+		{UserID: 1, GroupID: 2, Role: "member"},
+		{UserID: 2, GroupID: 1, Role: "member"},
+	}
+	if err := repo.CreateMany(ctx, &memberships); err != nil {
+		t.Fatalf("failed to seed memberships: %v", err)
+	}
+	return repo
+}
+
+func TestGetByKeysMatchesEveryKeyColumn(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	repo := seedMemberships(t, ctx, DB)
+
+	found, err := repo.GetByKeys(ctx, map[string]any{"user_id": 1, "group_id": 2})
+	if err != nil {
+		t.Fatalf("expected membership to be found, got %v", err)
+	}
+	if found.Role != "member" {
+		t.Errorf("expected role %q, got %q", "member", found.Role)
+	}
+
+	if _, err := repo.GetByKeys(ctx, map[string]any{"user_id": 2, "group_id": 2}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected gorm.ErrRecordNotFound, got %v", err)
+	}
+	if _, err := repo.GetByKeys(ctx, nil); !errors.Is(err, ormpgsql.ErrMissingKeys) {
+		t.Errorf("expected ErrMissingKeys, got %v", err)
+	}
+}
+
+func TestDeleteByKeysRemovesOnlyTheMatchingRow(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	repo := seedMemberships(t, ctx, DB)
+
+	if err := repo.DeleteByKeys(ctx, map[string]any{"user_id": 1, "group_id": 1}); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+
+	if _, err := repo.GetByKeys(ctx, map[string]any{"user_id": 1, "group_id": 1}); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("expected deleted membership to be gone, got %v", err)
+	}
+	remaining, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("failed to count memberships: %v", err)
+	}
+	if remaining != 2 {
+		t.Errorf("expected 2 memberships to remain, got %d", remaining)
+	}
+}
+
+func TestDeleteByKeysLeavesSoftDeletedRecordsUntouched(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	var before postgrescontainer.User
+	if err := DB.First(&before, 10).Error; err != nil {
+		t.Fatalf("failed to load user 10: %v", err)
+	}
+
+	// The seed soft deletes user 10.
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.DeleteByKeys(ctx, map[string]any{"id": 10}); err != nil {
+		t.Fatalf("expected delete to succeed, got %v", err)
+	}
+
+	var after postgrescontainer.User
+	if err := DB.First(&after, 10).Error; err != nil {
+		t.Fatalf("failed to reload user 10: %v", err)
+	}
+	if *after.DeletedAt != *before.DeletedAt {
+		t.Errorf("expected deleted_at to stay %s, got %s", *before.DeletedAt, *after.DeletedAt)
+	}
+}
+
+// versionedItem is a model whose Version field enables optimistic locking.
+type versionedItem struct {
+	ID      int
+	Name    string
+	Version int
+}
+
+func TestUpdateIncrementsVersionOfVersionedModels(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&versionedItem{}); err != nil {
+		t.Fatalf("failed to migrate versioned item: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[versionedItem](DB)
+	if err := repo.Create(ctx, &versionedItem{ID: 1, Name: "draft", Version: 1}); err != nil {
+		t.Fatalf("failed to create versioned item: %v", err)
+	}
+
+	item, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected item to be found, got %v", err)
+	}
+	item.Name = "published"
+	if err := repo.Update(ctx, item); err != nil {
+		t.Fatalf("expected versioned update to succeed, got %v", err)
+	}
+	if item.Version != 2 {
+		t.Errorf("expected version 2 after update, got %d", item.Version)
+	}
+
+	stored, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected item to be found, got %v", err)
+	}
+	if stored.Name != "published" || stored.Version != 2 {
+		t.Errorf("expected stored item to be published at version 2, got %+v", stored)
+	}
+}
+
+func TestUpdateReturnsErrOptimisticLockForStaleVersion(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&versionedItem{}); err != nil {
+		t.Fatalf("failed to migrate versioned item: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[versionedItem](DB)
+	if err := repo.Create(ctx, &versionedItem{ID: 1, Name: "draft", Version: 1}); err != nil {
+		t.Fatalf("failed to create versioned item: %v", err)
+	}
+
+	first, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected item to be found, got %v", err)
+	}
+	second, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected item to be found, got %v", err)
+	}
+
+	first.Name = "first writer"
+	if err := repo.Update(ctx, first); err != nil {
+		t.Fatalf("expected first update to succeed, got %v", err)
+	}
+
+	second.Name = "second writer"
+	if err := repo.Update(ctx, second); !errors.Is(err, ormpgsql.ErrOptimisticLock) {
+		t.Fatalf("expected ErrOptimisticLock, got %v", err)
+	}
+	if second.Version != 1 {
+		t.Errorf("expected the stale entity to keep version 1, got %d", second.Version)
+	}
+
+	stored, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected item to be found, got %v", err)
+	}
+	if stored.Name != "first writer" {
+		t.Errorf("expected the first write to win, got %q", stored.Name)
+	}
+}
+
+// versionedNote is a versioned model that also supports soft deletion.
+type versionedNote struct {
+	ID        int
+	Name      string
+	Version   int
+	DeletedAt *time.Time
+}
+
+func TestUpdateDoesNotWriteSoftDeletedVersionedRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&versionedNote{}); err != nil {
+		t.Fatalf("failed to migrate versioned note: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[versionedNote](DB)
+	if err := repo.Create(ctx, &versionedNote{ID: 1, Name: "draft", Version: 1}); err != nil {
+		t.Fatalf("failed to create versioned note: %v", err)
+	}
+	note, err := repo.GetById(ctx, "1")
+	if err != nil {
+		t.Fatalf("expected note to be found, got %v", err)
+	}
+	if err := repo.Delete(ctx, "1"); err != nil {
+		t.Fatalf("expected soft delete to succeed, got %v", err)
+	}
+
+	note.Name = "revived"
+	if err := repo.Update(ctx, note); !errors.Is(err, ormpgsql.ErrOptimisticLock) {
+		t.Fatalf("expected ErrOptimisticLock for a soft-deleted note, got %v", err)
+	}
+
+	var stored versionedNote
+	if err := DB.First(&stored, 1).Error; err != nil {
+		t.Fatalf("expected soft-deleted note to still exist, got %v", err)
+	}
+	if stored.DeletedAt == nil || stored.Name != "draft" || stored.Version != 1 {
+		t.Errorf("expected the soft-deleted note to be left unchanged, got %+v", stored)
+	}
+}
+
+// batchItem is a model whose check constraint lets tests force a mid-batch failure.
+type batchItem struct {
+	ID   int
+	Name string `gorm:"not null;check:name <> ''"`
+}
+
+func seedBatchItems(t *testing.T, ctx context.Context, repo *ormpgsql.GenericRepository[batchItem], count int) []batchItem {
+	items := make([]batchItem, count)
+	for i := range items {
+		items[i] = batchItem{ID: i + 1, Name: fmt.Sprintf("item %d", i+1)}
+	}
+	if err := repo.CreateMany(ctx, &items); err != nil {
+		t.Fatalf("failed to seed batch items: %v", err)
+	}
+	return items
+}
+
+func TestUpdateManyUpdatesAllRowsInBatches(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&batchItem{}); err != nil {
+		t.Fatalf("failed to migrate batch item: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[batchItem](DB).WithBatchSize(100)
+	items := seedBatchItems(t, ctx, repo, 500)
+
+	for i := range items {
+		items[i].Name = fmt.Sprintf("updated %d", items[i].ID)
+	}
+	if err := repo.UpdateMany(ctx, &items); err != nil {
+		t.Fatalf("expected UpdateMany to succeed, got %v", err)
+	}
+
+	var updated int64
+	if err := DB.Model(&batchItem{}).Where("name LIKE ?", "updated %").Count(&updated).Error; err != nil {
+		t.Fatalf("failed to count updated items: %v", err)
+	}
+	if updated != 500 {
+		t.Errorf("expected 500 updated rows, got %d", updated)
+	}
+
+	got, err := repo.GetById(ctx, "250")
+	if err != nil {
+		t.Fatalf("expected item 250 to be found, got %v", err)
+	}
+	if got.Name != "updated 250" {
+		t.Errorf("expected name %q, got %q", "updated 250", got.Name)
+	}
+}
+
+func TestUpdateManyRollsBackOnMidBatchError(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&batchItem{}); err != nil {
+		t.Fatalf("failed to migrate batch item: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[batchItem](DB).WithBatchSize(100)
+	items := seedBatchItems(t, ctx, repo, 300)
+
+	for i := range items {
+		items[i].Name = fmt.Sprintf("updated %d", items[i].ID)
+	}
+	// Violates the check constraint in the third batch.
+	items[250].Name = ""
+
+	if err := repo.UpdateMany(ctx, &items); err == nil {
+		t.Fatal("expected UpdateMany to fail, got nil")
+	}
+
+	var updated int64
+	if err := DB.Model(&batchItem{}).Where("name LIKE ?", "updated %").Count(&updated).Error; err != nil {
+		t.Fatalf("failed to count updated items: %v", err)
+	}
+	if updated != 0 {
+		t.Errorf("expected earlier batches to be rolled back, got %d updated rows", updated)
+	}
+}
+
+func TestUpdateManyDoesNotReviveSoftDeletedRecords(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	// The seed soft deletes user 10 but not user 9.
+	var users []postgrescontainer.User
+	if err := DB.Order("id").Find(&users, []int{9, 10}).Error; err != nil {
+		t.Fatalf("failed to load users: %v", err)
+	}
+	deletedName := users[1].Name
+	for i := range users {
+		users[i].Name = "Updated"
+		users[i].DeletedAt = nil
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if err := repo.UpdateMany(ctx, &users); err != nil {
+		t.Fatalf("expected UpdateMany to succeed, got %v", err)
+	}
+
+	var live, deleted postgrescontainer.User
+	if err := DB.First(&live, 9).Error; err != nil {
+		t.Fatalf("failed to reload user 9: %v", err)
+	}
+	if live.Name != "Updated" {
+		t.Errorf("expected user 9 to be updated, got name %q", live.Name)
+	}
+	if err := DB.First(&deleted, 10).Error; err != nil {
+		t.Fatalf("failed to reload user 10: %v", err)
+	}
+	if deleted.DeletedAt == nil || deleted.Name != deletedName {
+		t.Errorf("expected soft-deleted user 10 to be left untouched, got deleted_at %v and name %q", deleted.DeletedAt, deleted.Name)
+	}
+}
+
+func TestCreateInBatchesInsertsBeyondTheParameterLimit(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&batchItem{}); err != nil {
+		t.Fatalf("failed to migrate batch item: %v", err)
+	}
+
+	// Two columns per row put 40,000 rows well past postgres' 65,535 bind parameters per statement.
+	items := make([]batchItem, 40000)
+	for i := range items {
+		items[i] = batchItem{ID: i + 1, Name: fmt.Sprintf("item %d", i+1)}
+	}
+
+	repo := ormpgsql.NewGenericRepository[batchItem](DB)
+	if err := repo.CreateInBatches(ctx, &items, 1000); err != nil {
+		t.Fatalf("expected batched insert to succeed, got %v", err)
+	}
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("failed to count batch items: %v", err)
+	}
+	if count != int64(len(items)) {
+		t.Errorf("expected %d rows, got %d", len(items), count)
+	}
+}
+
+func TestCreateInBatchesRollsBackOnError(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&batchItem{}); err != nil {
+		t.Fatalf("failed to migrate batch item: %v", err)
+	}
+
+	items := make([]batchItem, 300)
+	for i := range items {
+		items[i] = batchItem{ID: i + 1, Name: fmt.Sprintf("item %d", i+1)}
+	}
+	items[250].Name = ""
+
+	repo := ormpgsql.NewGenericRepository[batchItem](DB)
+	if err := repo.CreateInBatches(ctx, &items, 100); err == nil {
+		t.Fatal("expected the check constraint to fail the insert, got nil")
+	}
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("failed to count batch items: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("expected earlier batches to be rolled back, got %d rows", count)
+	}
+}
+
+func TestEachBatchVisitsEveryRowExactlyOnce(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&batchItem{}); err != nil {
+		t.Fatalf("failed to migrate batch item: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[batchItem](DB)
+	items := seedBatchItems(t, ctx, repo, 300)
+
+	seen := make(map[int]int, len(items))
+	batches := 0
+	err = repo.EachBatch(ctx, 64, func(batch []batchItem) error {
+		batches++
+		if len(batch) > 64 {
+			t.Errorf("expected at most 64 items per batch, got %d", len(batch))
+		}
+		for _, item := range batch {
+			seen[item.ID]++
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected EachBatch to succeed, got %v", err)
+	}
+
+	if batches != 5 {
+		t.Errorf("expected 5 batches, got %d", batches)
+	}
+	if len(seen) != len(items) {
+		t.Errorf("expected %d distinct items, got %d", len(items), len(seen))
+	}
+	for id, count := range seen {
+		if count != 1 {
+			t.Errorf("expected item %d to be seen once, got %d", id, count)
+		}
+	}
+}
+
+func TestEachBatchSkipsSoftDeletedRows(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	seen := 0
+	err = repo.EachBatch(ctx, 10, func(batch []postgrescontainer.User) error {
+		for _, user := range batch {
+			if user.DeletedAt != nil {
+				t.Errorf("expected soft-deleted user %d to be skipped", user.ID)
+			}
+		}
+		seen += len(batch)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected EachBatch to succeed, got %v", err)
+	}
+	if seen != 36 {
+		t.Errorf("expected 36 active users, got %d", seen)
+	}
+}
+
+func TestEachBatchStopsWhenContextIsCancelled(t *testing.T) {
+	DB, err := postgrescontainer.Start(context.Background(), t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	batches := 0
+	err = repo.EachBatch(ctx, 10, func(batch []postgrescontainer.User) error {
+		batches++
+		cancel()
+		return nil
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if batches != 1 {
+		t.Errorf("expected iteration to stop after 1 batch, got %d", batches)
+	}
+}
+
+func TestFindWhereFiltersAppliesEveryComparison(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	users, err := repo.FindWhereFilters(ctx, []*pagination.FilterModel{
+		{Field: "total_spent", Value: "1000,2000", Comparison: "between"},
+		{Field: "is_active", Comparison: "is_true"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error from FindWhereFilters, got %v", err)
+	}
+
+	// Alice and users 12, 14, 16 and 18 are active and spent 1000-2000; users 10 and 20 are soft deleted.
+	if len(*users) != 5 {
+		t.Fatalf("expected 5 users, got %d", len(*users))
+	}
+	for _, user := range *users {
+		if user.TotalSpent < 1000 || user.TotalSpent > 2000 || !user.IsActive || user.DeletedAt != nil {
+			t.Errorf("expected only active users who spent 1000-2000, got %+v", user)
+		}
+	}
+}
+
+func TestFindWhereFiltersReturnsFilterErrors(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	if _, err := repo.FindWhereFilters(ctx, []*pagination.FilterModel{
+		{Field: "age", Value: "30", Comparison: "unknown"},
+	}); err == nil {
+		t.Fatal("expected an error for an unsupported comparison, got nil")
+	}
+}
+
+func TestFindPaginatedAppliesFiltersThroughRepository(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+	listQuery := &pagination.ListQuery{
+		Size:    5,
+		Page:    1,
+		OrderBy: "id ASC",
+		Filters: []*pagination.FilterModel{
+			{Field: "is_admin", Comparison: "is_false"},
+			{Field: "age", Value: "30", Comparison: ">="},
+		},
+	}
+
+	result, err := repo.FindPaginated(ctx, listQuery)
+	if err != nil {
+		t.Fatalf("expected no error from FindPaginated, got %v", err)
+	}
+	if len(result.Data) != 5 {
+		t.Fatalf("expected 5 users on the page, got %d", len(result.Data))
+	}
+	if result.Page != 1 || result.Size != 5 {
+		t.Errorf("expected page 1 of size 5, got page %d of size %d", result.Page, result.Size)
+	}
+
+	for _, user := range result.Data {
+		if user.IsAdmin || user.Age < 30 {
+			t.Errorf("expected only non-admin users aged 30 or more, got %+v", user)
+		}
+		if user.DeletedAt != nil {
+			t.Errorf("expected soft-deleted user %d to be excluded", user.ID)
+		}
+	}
+}
+
+// customer and purchase are soft-deletable models joined through the Customer association.
+type customer struct {
+	ID        int
+	Name      string
+	DeletedAt *time.Time
+}
+
+type purchase struct {
+	ID         int
+	CustomerID int
+	Customer   customer
+	DeletedAt  *time.Time
+}
+
+func TestFindPaginatedJoinsTablesWithDeletedAt(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	if err := DB.AutoMigrate(&customer{}, &purchase{}); err != nil {
+		t.Fatalf("failed to migrate customers and purchases: %v", err)
+	}
+
+	deletedAt := time.Now()
+	customers := []customer{{Name: "Ada"}, {Name: "Grace", DeletedAt: &deletedAt}}
+	if err := DB.Create(&customers).Error; err != nil {
+		t.Fatalf("failed to seed customers: %v", err)
+	}
+	purchases := []purchase{
+		{CustomerID: customers[0].ID},
+		{CustomerID: customers[0].ID, DeletedAt: &deletedAt},
+		{CustomerID: customers[1].ID},
+	}
+	if err := DB.Create(&purchases).Error; err != nil {
+		t.Fatalf("failed to seed purchases: %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[purchase](DB)
+	result, err := repo.FindPaginated(ctx, &pagination.ListQuery{Size: 10, Page: 1, Joins: []string{"Customer"}})
+	if err != nil {
+		t.Fatalf("expected no error from FindPaginated, got %v", err)
+	}
+
+	// Only soft-deleted purchases are excluded; the join does not filter on the customer's deleted_at.
+	if len(result.Data) != 2 {
+		t.Fatalf("expected 2 purchases, got %d", len(result.Data))
+	}
+	for _, found := range result.Data {
+		if found.ID == purchases[1].ID {
+			t.Errorf("expected soft-deleted purchase %d to be excluded", found.ID)
+		}
+		if found.Customer.ID != found.CustomerID {
+			t.Errorf("expected purchase %d to be joined with customer %d, got %+v", found.ID, found.CustomerID, found.Customer)
+		}
+	}
+}
+
+// lockUsersTable holds an exclusive lock on the users table until the test ends,
+// so that any query against it blocks.
+func lockUsersTable(t *testing.T, DB *gorm.DB) {
+	tx := DB.Begin()
+	if err := tx.Exec("LOCK TABLE users IN ACCESS EXCLUSIVE MODE").Error; err != nil {
+		t.Fatalf("failed to lock users table: %v", err)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+}
+
+func TestRepositoryTimeoutAbortsSlowQuery(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	lockUsersTable(t, DB)
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB).WithTimeout(200 * time.Millisecond)
+
+	start := time.Now()
+	_, err = repo.Count(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected Count to return promptly after the timeout, took %v", elapsed)
+	}
+}
+
+func TestRepositoryPropagatesCancellationMidQuery(t *testing.T) {
+	DB, err := postgrescontainer.Start(context.Background(), t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+	lockUsersTable(t, DB)
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](DB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(200*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err = repo.GetAll(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected GetAll to return promptly after cancellation, took %v", elapsed)
+	}
+}
+
+func TestReadReplicasServeRepositoryReads(t *testing.T) {
+	ctx := context.Background()
+	_, replicaCfg, err := postgrescontainer.StartWithConfig(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	// The seeded test database acts as the replica and an empty database on the same server as the primary.
+	primaryCfg := *replicaCfg
+	primaryCfg.DBName = "primary_db"
+
+	primaryOnly, err := ormpgsql.NewORM(&primaryCfg)
+	if err != nil {
+		t.Fatalf("failed to create primary database: %v", err)
+	}
+	if err := primaryOnly.AutoMigrate(&postgrescontainer.User{}); err != nil {
+		t.Fatalf("failed to migrate primary database: %v", err)
+	}
+
+	routedCfg := primaryCfg
+	routedCfg.ReadReplicas = []ormpgsql.PostgresConfig{*replicaCfg}
+
+	routed, err := ormpgsql.NewORM(&routedCfg)
+	if err != nil {
+		t.Fatalf("expected no error creating ORM with replicas, got %v", err)
+	}
+
+	repo := ormpgsql.NewGenericRepository[postgrescontainer.User](routed)
+
+	count, err := repo.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count: unexpected error: %v", err)
+	}
+	if count != 36 {
+		t.Errorf("expected reads to hit the seeded replica with 36 active users, got %d", count)
+	}
+
+	if err := repo.Create(ctx, &postgrescontainer.User{ID: 500, Name: "Primary Write"}); err != nil {
+		t.Fatalf("Create: unexpected error: %v", err)
+	}
+
+	var primaryCount int64
+	if err := primaryOnly.Model(&postgrescontainer.User{}).Count(&primaryCount).Error; err != nil {
+		t.Fatalf("failed to count primary users: %v", err)
+	}
+	if primaryCount != 1 {
+		t.Errorf("expected the write to land on the primary, got %d rows", primaryCount)
+	}
+}
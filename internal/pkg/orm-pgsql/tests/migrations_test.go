@@ -0,0 +1,155 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	postgrescontainer "github.com/NekKkMirror/go-app/internal/pkg/container/test/postgres"
+	"github.com/NekKkMirror/go-app/internal/pkg/orm-pgsql"
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+type widget struct {
+	ID   int
+	Name string
+}
+
+type gadget struct {
+	ID   int
+	Name string
+}
+
+func createTableMigration(id string, model interface{}) ormpgsql.Migration {
+	return ormpgsql.Migration{
+		ID:   id,
+		Up:   func(tx *gorm.DB) error { return tx.Migrator().CreateTable(model) },
+		Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(model) },
+	}
+}
+
+func TestMigratorAppliesAndRollsBackBatches(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	first := createTableMigration("0001_create_widgets", &widget{})
+	second := createTableMigration("0002_create_gadgets", &gadget{})
+
+	if err := ormpgsql.NewMigrator(DB, first).Up(ctx); err != nil {
+		t.Fatalf("expected first batch to apply, got %v", err)
+	}
+	migrator := ormpgsql.NewMigrator(DB, first, second)
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("expected second batch to apply, got %v", err)
+	}
+	if !DB.Migrator().HasTable(&widget{}) || !DB.Migrator().HasTable(&gadget{}) {
+		t.Fatal("expected both tables to exist after applying migrations")
+	}
+
+	if err := migrator.Rollback(ctx); err != nil {
+		t.Fatalf("expected rollback to succeed, got %v", err)
+	}
+	if DB.Migrator().HasTable(&gadget{}) {
+		t.Error("expected the last batch to be rolled back")
+	}
+	if !DB.Migrator().HasTable(&widget{}) {
+		t.Error("expected earlier batches to remain applied")
+	}
+
+	var recorded int64
+	if err := DB.Table("schema_migrations").Count(&recorded).Error; err != nil {
+		t.Fatalf("failed to count applied migrations: %v", err)
+	}
+	if recorded != 1 {
+		t.Errorf("expected 1 recorded migration, got %d", recorded)
+	}
+}
+
+func TestMigratorRefusesToRollBackIrreversibleMigration(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	migration := createTableMigration("0001_create_widgets", &widget{})
+	migration.Down = nil
+
+	migrator := ormpgsql.NewMigrator(DB, migration)
+	if err := migrator.Up(ctx); err != nil {
+		t.Fatalf("expected migration to apply, got %v", err)
+	}
+	if err := migrator.Rollback(ctx); !errors.Is(err, ormpgsql.ErrIrreversibleMigration) {
+		t.Fatalf("expected ErrIrreversibleMigration, got %v", err)
+	}
+	if !DB.Migrator().HasTable(&widget{}) {
+		t.Error("expected the table to remain after a refused rollback")
+	}
+}
+
+func TestDropTablesRemovesMigratedTables(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	if err := ormpgsql.Migrate(ctx, DB, &widget{}, &gadget{}); err != nil {
+		t.Fatalf("expected migrate to succeed, got %v", err)
+	}
+	if err := ormpgsql.DropTables(DB, &widget{}, &gadget{}); err != nil {
+		t.Fatalf("expected drop to succeed, got %v", err)
+	}
+	if DB.Migrator().HasTable(&widget{}) || DB.Migrator().HasTable(&gadget{}) {
+		t.Error("expected both tables to be dropped")
+	}
+}
+
+// brokenModel declares a column type postgres does not know, so migrating it always fails.
+type brokenModel struct {
+	ID    int
+	Value string `gorm:"type:not_a_real_type"`
+}
+
+func TestMigrateNamesTheTypeThatFailed(t *testing.T) {
+	ctx := context.Background()
+	DB, err := postgrescontainer.Start(ctx, t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	err = ormpgsql.Migrate(ctx, DB, &widget{}, &brokenModel{}, &gadget{})
+	if err == nil {
+		t.Fatal("expected migration to fail, got nil")
+	}
+	if !strings.Contains(err.Error(), "tests.brokenModel") {
+		t.Errorf("expected the error to name tests.brokenModel, got %v", err)
+	}
+	if !DB.Migrator().HasTable(&widget{}) {
+		t.Error("expected models before the failing one to be migrated")
+	}
+	if DB.Migrator().HasTable(&gadget{}) {
+		t.Error("expected models after the failing one to be skipped")
+	}
+}
+
+func TestMigrateStopsWhenContextIsCancelled(t *testing.T) {
+	DB, err := postgrescontainer.Start(context.Background(), t)
+	if err != nil {
+		t.Fatalf("expected no error from DB, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if err := ormpgsql.Migrate(ctx, DB, &widget{}); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if DB.Migrator().HasTable(&widget{}) {
+		t.Error("expected no table to be created after cancellation")
+	}
+}
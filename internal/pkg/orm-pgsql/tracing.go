@@ -0,0 +1,79 @@
+package ormpgsql
+
+import (
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	// tracerName identifies the instrumentation scope of ORM query spans.
+	tracerName = "github.com/NekKkMirror/go-app/internal/pkg/orm-pgsql"
+
+	// spanInstanceKey is the gorm instance key under which the active span is stored.
+	spanInstanceKey = "otel:span"
+)
+
+// RegisterTracing registers gorm callbacks that wrap every statement in an OpenTelemetry span
+// carrying the SQL and the number of affected rows. Spans are created from the global tracer provider.
+func RegisterTracing(db *gorm.DB) error {
+	callbacks := db.Callback()
+
+	for _, err := range []error{
+		callbacks.Create().Before("gorm:create").Register("otel:before_create", startSpan("create")),
+		callbacks.Create().After("gorm:create").Register("otel:after_create", endSpan),
+		callbacks.Query().Before("gorm:query").Register("otel:before_query", startSpan("query")),
+		callbacks.Query().After("gorm:query").Register("otel:after_query", endSpan),
+		callbacks.Update().Before("gorm:update").Register("otel:before_update", startSpan("update")),
+		callbacks.Update().After("gorm:update").Register("otel:after_update", endSpan),
+		callbacks.Delete().Before("gorm:delete").Register("otel:before_delete", startSpan("delete")),
+		callbacks.Delete().After("gorm:delete").Register("otel:after_delete", endSpan),
+		callbacks.Row().Before("gorm:row").Register("otel:before_row", startSpan("row")),
+		callbacks.Row().After("gorm:row").Register("otel:after_row", endSpan),
+		callbacks.Raw().Before("gorm:raw").Register("otel:before_raw", startSpan("raw")),
+		callbacks.Raw().After("gorm:raw").Register("otel:after_raw", endSpan),
+	} {
+		if err != nil {
+			return errors.Wrap(err, "failed to register tracing callbacks")
+		}
+	}
+
+	return nil
+}
+
+// startSpan returns a callback that starts a span for the given operation and stores it on the statement.
+func startSpan(operation string) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		ctx, span := otel.Tracer(tracerName).Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "postgresql")),
+		)
+		db.Statement.Context = ctx
+		db.InstanceSet(spanInstanceKey, span)
+	}
+}
+
+// endSpan finishes the span started for the statement, recording the SQL, affected rows and any error.
+func endSpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("db.statement", db.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+}
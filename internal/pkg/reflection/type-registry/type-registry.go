@@ -2,11 +2,29 @@ package typeregistry
 
 import (
 	"reflect"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	ErrTypeNotRegistered = errors.New("typeregistry: type is not registered")
+
+	ErrTypeMismatch = errors.New("typeregistry: registered type does not match requested type")
 )
 
 // Global type registry mapping type names to their reflect.Type.
 var typeRegistry = make(map[string]reflect.Type)
 
+// Aliases mapping short, user-chosen names to their reflect.Type.
+var aliasRegistry = make(map[string]reflect.Type)
+
+// Factories mapping names to constructors that build initialized instances.
+var factoryRegistry = make(map[string]func() any)
+
+// registryMu guards the registries against concurrent registration and lookup.
+var registryMu sync.RWMutex
+
 type MyString string
 type myString string
 
@@ -24,14 +42,107 @@ func initializeTypeRegistry() {
 func registerType(typedNil interface{}) {
 	t := reflect.TypeOf(typedNil).Elem()
 	typeName := t.PkgPath() + "." + t.Name()
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	typeRegistry[typeName] = t
 }
 
-// makeInstance creates a new instance of a type registered in the typeRegistry map.
-func makeInstance(name string) (interface{}, bool) {
-	typ, exists := typeRegistry[name]
+// RegisterAlias registers the type of v under a short alias such as "user".
+// v may be a value or a typed nil pointer, in which case the pointed-to type is registered.
+// An untyped nil carries no type, so the alias is left unregistered.
+func RegisterAlias(alias string, v any) {
+	t := reflect.TypeOf(v)
+	if t == nil {
+		return
+	}
+	if t.Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil() {
+		t = t.Elem()
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	aliasRegistry[alias] = t
+}
+
+// RegisterFactory registers a constructor under name that NewInstance uses instead of a zero value.
+func RegisterFactory(name string, fn func() any) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	factoryRegistry[name] = fn
+}
+
+// Unregister removes the type or factory registered under name, whether it is a full type name or an alias.
+func Unregister(name string) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	delete(typeRegistry, name)
+	delete(aliasRegistry, name)
+	delete(factoryRegistry, name)
+}
+
+// Reset removes every registered type, alias and factory, which is mainly useful for isolating tests.
+func Reset() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	typeRegistry = make(map[string]reflect.Type)
+	aliasRegistry = make(map[string]reflect.Type)
+	factoryRegistry = make(map[string]func() any)
+}
+
+// lookupType resolves a registered type by its full name, falling back to aliases.
+func lookupType(name string) (reflect.Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	if typ, exists := typeRegistry[name]; exists {
+		return typ, true
+	}
+	typ, exists := aliasRegistry[name]
+	return typ, exists
+}
+
+// lookupFactory resolves a registered factory by name.
+func lookupFactory(name string) (func() any, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	fn, exists := factoryRegistry[name]
+	return fn, exists
+}
+
+// makeInstance creates a new instance of a type registered in the typeRegistry map or under an alias.
+// A factory registered under the same name takes precedence over zero-value construction.
+func makeInstance(name string) (interface{}, error) {
+	if fn, exists := lookupFactory(name); exists {
+		return fn(), nil
+	}
+
+	typ, exists := lookupType(name)
 	if !exists {
-		return nil, false
+		return nil, errors.Wrapf(ErrTypeNotRegistered, "name: %s", name)
+	}
+	return reflect.New(typ).Elem().Interface(), nil
+}
+
+// NewInstance creates a new instance of the type registered under name or alias.
+func NewInstance(name string) (interface{}, error) {
+	return makeInstance(name)
+}
+
+// New creates a new instance of the type registered under name and asserts it to T.
+// It returns the zero value of T and an error when the name is unknown or the type does not match.
+func New[T any](name string) (T, error) {
+	var zero T
+
+	instance, err := NewInstance(name)
+	if err != nil {
+		return zero, err
+	}
+
+	typed, ok := instance.(T)
+	if !ok {
+		return zero, errors.Wrapf(ErrTypeMismatch, "name: %s, got %T", name, instance)
 	}
-	return reflect.New(typ).Elem().Interface(), true
+	return typed, nil
 }
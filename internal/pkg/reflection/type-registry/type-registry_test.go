@@ -2,7 +2,11 @@ package typeregistry
 
 import (
 	"reflect"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 const (
@@ -44,3 +48,202 @@ func TestMakeInstance(t *testing.T) {
 		t.Errorf("Expected instance of type myString, got %T", instance)
 	}
 }
+
+func TestConcurrentRegisterAndMakeInstance(t *testing.T) {
+	typeRegistry = make(map[string]reflect.Type)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registerType((*MyString)(nil))
+			registerType((*myString)(nil))
+		}()
+		go func() {
+			defer wg.Done()
+			_, _ = makeInstance(pubKey)
+			_, _ = makeInstance(priKey)
+		}()
+	}
+	wg.Wait()
+
+	if _, err := makeInstance(pubKey); err != nil {
+		t.Errorf("Expected typeregistry.MyString to be registered, got %v", err)
+	}
+	if _, err := makeInstance(priKey); err != nil {
+		t.Errorf("Expected typeregistry.myString to be registered, got %v", err)
+	}
+}
+
+func TestNewReturnsTypedInstance(t *testing.T) {
+	typeRegistry = make(map[string]reflect.Type)
+	registerType((*MyString)(nil))
+
+	instance, err := New[MyString](pubKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if instance != "" {
+		t.Errorf("Expected zero value of MyString, got %q", instance)
+	}
+}
+
+func TestNewReturnsErrorOnTypeMismatch(t *testing.T) {
+	typeRegistry = make(map[string]reflect.Type)
+	registerType((*MyString)(nil))
+
+	instance, err := New[myString](pubKey)
+	if !errors.Is(err, ErrTypeMismatch) {
+		t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+	}
+	if instance != "" {
+		t.Errorf("Expected zero value on mismatch, got %q", instance)
+	}
+}
+
+func TestNewReturnsErrorForUnknownName(t *testing.T) {
+	typeRegistry = make(map[string]reflect.Type)
+
+	_, err := New[MyString]("unknown.Type")
+	if !errors.Is(err, ErrTypeNotRegistered) {
+		t.Fatalf("Expected ErrTypeNotRegistered, got %v", err)
+	}
+}
+
+func TestRegisterAliasAndNewInstance(t *testing.T) {
+	aliasRegistry = make(map[string]reflect.Type)
+
+	RegisterAlias("my-string", (*MyString)(nil))
+	RegisterAlias("private-string", myString(""))
+
+	instance, err := NewInstance("my-string")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok := instance.(MyString); !ok {
+		t.Errorf("Expected instance of type MyString, got %T", instance)
+	}
+
+	typed, err := New[myString]("private-string")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if typed != "" {
+		t.Errorf("Expected zero value of myString, got %q", typed)
+	}
+}
+
+func TestRegisterAliasIgnoresUntypedNil(t *testing.T) {
+	aliasRegistry = make(map[string]reflect.Type)
+
+	RegisterAlias("nothing", nil)
+
+	if _, err := NewInstance("nothing"); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Fatalf("Expected ErrTypeNotRegistered, got %v", err)
+	}
+}
+
+func TestNewInstanceReturnsErrorForUnknownAlias(t *testing.T) {
+	aliasRegistry = make(map[string]reflect.Type)
+
+	if _, err := NewInstance("user"); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Fatalf("Expected ErrTypeNotRegistered, got %v", err)
+	}
+}
+
+func TestMakeInstanceReturnsErrorForUnknownName(t *testing.T) {
+	typeRegistry = make(map[string]reflect.Type)
+
+	instance, err := makeInstance(pubKey)
+	if !errors.Is(err, ErrTypeNotRegistered) {
+		t.Fatalf("Expected ErrTypeNotRegistered, got %v", err)
+	}
+	if instance != nil {
+		t.Errorf("Expected nil instance, got %v", instance)
+	}
+
+	registerType((*MyString)(nil))
+
+	fromMake, err := makeInstance(pubKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	fromNew, err := NewInstance(pubKey)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if reflect.TypeOf(fromMake) != reflect.TypeOf(fromNew) {
+		t.Errorf("Expected makeInstance and NewInstance to agree, got %T and %T", fromMake, fromNew)
+	}
+}
+
+func TestUnregisterRemovesTypeAndAlias(t *testing.T) {
+	Reset()
+
+	registerType((*MyString)(nil))
+	RegisterAlias("my-string", (*MyString)(nil))
+
+	if _, err := NewInstance(pubKey); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	Unregister(pubKey)
+	Unregister("my-string")
+
+	if _, err := NewInstance(pubKey); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Errorf("Expected ErrTypeNotRegistered after Unregister, got %v", err)
+	}
+	if _, err := NewInstance("my-string"); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Errorf("Expected ErrTypeNotRegistered for alias after Unregister, got %v", err)
+	}
+}
+
+func TestResetClearsRegistry(t *testing.T) {
+	registerType((*MyString)(nil))
+	RegisterAlias("my-string", (*MyString)(nil))
+
+	Reset()
+
+	if _, err := NewInstance(pubKey); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Errorf("Expected ErrTypeNotRegistered after Reset, got %v", err)
+	}
+	if _, err := NewInstance("my-string"); !errors.Is(err, ErrTypeNotRegistered) {
+		t.Errorf("Expected ErrTypeNotRegistered for alias after Reset, got %v", err)
+	}
+}
+
+func TestRegisterFactoryInitializesInstance(t *testing.T) {
+	Reset()
+
+	type entity struct {
+		Name      string
+		CreatedAt time.Time
+	}
+
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	RegisterFactory("entity", func() any {
+		return entity{CreatedAt: createdAt}
+	})
+
+	instance, err := NewInstance("entity")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	e, ok := instance.(entity)
+	if !ok {
+		t.Fatalf("Expected instance of type entity, got %T", instance)
+	}
+	if !e.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected CreatedAt to be %v, got %v", createdAt, e.CreatedAt)
+	}
+
+	typed, err := New[entity]("entity")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !typed.CreatedAt.Equal(createdAt) {
+		t.Errorf("Expected CreatedAt to be %v, got %v", createdAt, typed.CreatedAt)
+	}
+}
@@ -0,0 +1,79 @@
+package runner
+
+import (
+	"context"
+	stderrors "errors"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Service is a long-running component, such as an HTTP or gRPC server, that runs until its context is cancelled.
+type Service struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Runner runs several services together and shuts them down in the order they were added.
+type Runner struct {
+	services        []Service
+	shutdownTimeout time.Duration
+}
+
+// NewRunner creates a new instance of Runner whose shutdown of all services must finish within shutdownTimeout.
+func NewRunner(shutdownTimeout time.Duration) *Runner {
+	return &Runner{shutdownTimeout: shutdownTimeout}
+}
+
+// Add registers a service. Services are stopped in the order they are added.
+func (r *Runner) Add(name string, run func(ctx context.Context) error) *Runner {
+	r.services = append(r.services, Service{Name: name, Run: run})
+	return r
+}
+
+// Run starts every service and blocks until ctx is cancelled or any service stops on its own.
+// It then cancels the services one at a time, in the order they were added, waiting for each to return
+// before stopping the next, all within a shared shutdown deadline. Errors returned by the services and
+// services that do not stop in time are collected into the returned error.
+func (r *Runner) Run(ctx context.Context) error {
+	type running struct {
+		name   string
+		cancel context.CancelFunc
+		done   chan error
+	}
+
+	stopped := make(chan struct{}, len(r.services))
+	runs := make([]running, 0, len(r.services))
+	for _, service := range r.services {
+		serviceCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		done := make(chan error, 1)
+		runs = append(runs, running{name: service.Name, cancel: cancel, done: done})
+
+		go func() {
+			done <- service.Run(serviceCtx)
+			stopped <- struct{}{}
+		}()
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-stopped:
+	}
+
+	deadline, cancel := context.WithTimeout(context.Background(), r.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, run := range runs {
+		run.cancel()
+		select {
+		case err := <-run.done:
+			if err != nil {
+				errs = append(errs, errors.Wrap(err, run.name))
+			}
+		case <-deadline.Done():
+			errs = append(errs, errors.Wrapf(deadline.Err(), "%s: shutdown did not complete", run.name))
+		}
+	}
+	return stderrors.Join(errs...)
+}
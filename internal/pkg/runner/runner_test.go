@@ -0,0 +1,87 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeServer records when it is stopped and returns stopErr once its context is cancelled.
+type fakeServer struct {
+	name    string
+	stopErr error
+	mu      *sync.Mutex
+	order   *[]string
+}
+
+func (s fakeServer) Run(ctx context.Context) error {
+	<-ctx.Done()
+	s.mu.Lock()
+	*s.order = append(*s.order, s.name)
+	s.mu.Unlock()
+	return s.stopErr
+}
+
+func TestRunnerStopsServicesInOrderAndCollectsErrors(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	httpErr, grpcErr := errors.New("http failed"), errors.New("grpc failed")
+	r := NewRunner(time.Second).
+		Add("http", fakeServer{name: "http", stopErr: httpErr, mu: &mu, order: &order}.Run).
+		Add("grpc", fakeServer{name: "grpc", stopErr: grpcErr, mu: &mu, order: &order}.Run)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		cancel()
+	}()
+
+	err := r.Run(ctx)
+
+	assert.Equal(t, []string{"http", "grpc"}, order)
+	assert.ErrorIs(t, err, httpErr)
+	assert.ErrorIs(t, err, grpcErr)
+	assert.Contains(t, err.Error(), "http: http failed")
+	assert.Contains(t, err.Error(), "grpc: grpc failed")
+}
+
+func TestRunnerStopsOthersWhenOneServiceFails(t *testing.T) {
+	var (
+		mu    sync.Mutex
+		order []string
+	)
+	startErr := errors.New("address already in use")
+	r := NewRunner(time.Second).
+		Add("http", func(ctx context.Context) error { return startErr }).
+		Add("grpc", fakeServer{name: "grpc", mu: &mu, order: &order}.Run)
+
+	err := r.Run(context.Background())
+
+	assert.ErrorIs(t, err, startErr)
+	assert.Equal(t, []string{"grpc"}, order)
+}
+
+func TestRunnerReportsServicesThatMissTheShutdownDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	r := NewRunner(50*time.Millisecond).
+		Add("stuck", func(ctx context.Context) error {
+			<-release
+			return nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := r.Run(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Contains(t, err.Error(), "stuck: shutdown did not complete")
+}
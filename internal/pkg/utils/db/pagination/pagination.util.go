@@ -21,6 +21,7 @@ type ListResult[T interface{}] struct {
 	Size            int    `json:"size,omitempty"            bson:"size"`
 	Page            int    `json:"page,omitempty"            bson:"page"`
 	TotalCount      int64  `json:"totalCount,omitempty"      bson:"totalCount"`
+	FilteredCount   int64  `json:"filteredCount,omitempty"   bson:"filteredCount"`
 	TotalPages      int    `json:"totalPages,omitempty"      bson:"totalPages"`
 	HasPreviousPage bool   `json:"hasPreviousPage,omitempty" bson:"hasPreviousPage"`
 	HasNextPage     bool   `json:"hasNextPage,omitempty"     bson:"hasNextPage"`
@@ -40,7 +41,13 @@ type ListResult[T interface{}] struct {
 
 // NewListResult constructs an instance of ListResult with calculated pagination details.
 func NewListResult[T any](size, page int, totalCount int64, data []T) *ListResult[T] {
-	totalPages := calculateTotalPages(size, totalCount)
+	return NewFilteredListResult(size, page, totalCount, totalCount, data)
+}
+
+// NewFilteredListResult constructs an instance of ListResult for a filtered query, where totalCount counts
+// every record and filteredCount only those matching the filters. Pagination details follow filteredCount.
+func NewFilteredListResult[T any](size, page int, totalCount, filteredCount int64, data []T) *ListResult[T] {
+	totalPages := calculateTotalPages(size, filteredCount)
 	firstItemIndex := (page - 1) * size
 	lastItemIndex := page * size
 
@@ -48,20 +55,21 @@ func NewListResult[T any](size, page int, totalCount int64, data []T) *ListResul
 		Size:            size,
 		Page:            page,
 		TotalCount:      totalCount,
+		FilteredCount:   filteredCount,
 		TotalPages:      totalPages,
 		FirstItemIndex:  firstItemIndex,
 		LastItemIndex:   lastItemIndex,
 		IsFirstPage:     page == 1,
-		IsLastPage:      lastItemIndex >= int(totalCount),
+		IsLastPage:      lastItemIndex >= int(filteredCount),
 		HasPreviousPage: page > 1,
-		HasNextPage:     lastItemIndex < int(totalCount),
+		HasNextPage:     lastItemIndex < int(filteredCount),
 		NextPage:        page + 1,
 		PreviousPage:    page - 1,
 		IsEmpty:         len(data) == 0,
 		HasSinglePage:   totalPages == 1,
-		HasMorePages:    lastItemIndex < int(totalCount),
+		HasMorePages:    lastItemIndex < int(filteredCount),
 		HasLessPages:    page > 1,
-		PaginationInfo:  fmt.Sprintf("Showing data %d to %d of %d", firstItemIndex+1, lastItemIndex, totalCount),
+		PaginationInfo:  fmt.Sprintf("Showing data %d to %d of %d", firstItemIndex+1, lastItemIndex, filteredCount),
 		Data:            data,
 	}
 }
@@ -73,10 +81,13 @@ func calculateTotalPages(size int, totalCount int64) int {
 
 // ListQuery represents the query parameters for pagination and filtering.
 type ListQuery struct {
-	Size    int            `query:"size"    json:"size,omitempty"`
-	Page    int            `query:"page"    json:"page,omitempty"`
-	OrderBy string         `query:"orderBy" json:"orderBy,omitempty"`
-	Filters []*FilterModel `query:"filters" json:"filters,omitempty"`
+	Size     int            `query:"size"     json:"size,omitempty"`
+	Page     int            `query:"page"     json:"page,omitempty"`
+	OrderBy  string         `query:"orderBy"  json:"orderBy,omitempty"`
+	Filters  []*FilterModel `query:"filters"  json:"filters,omitempty"`
+	Select   []string       `query:"select"   json:"select,omitempty"`
+	Preloads []string       `query:"preloads" json:"preloads,omitempty"`
+	Joins    []string       `query:"joins"    json:"joins,omitempty"`
 }
 
 // FilterModel represents the filtering model with field, value, and comparison parameters.
@@ -134,6 +145,9 @@ func GetListQueryFromCtx(c echo.Context) (*ListQuery, error) {
 		String("size", &size).
 		String("page", &page).
 		String("orderBy", &orderBy).
+		Strings("select", &q.Select).
+		Strings("preloads", &q.Preloads).
+		Strings("joins", &q.Joins).
 		BindError()
 
 	if err != nil {
@@ -204,6 +218,21 @@ func (q *ListQuery) GetOrderBy() string {
 	return q.OrderBy
 }
 
+// GetSelect returns the columns to fetch, where an empty slice means all columns.
+func (q *ListQuery) GetSelect() []string {
+	return q.Select
+}
+
+// GetPreloads returns the associations to load with separate queries alongside the page.
+func (q *ListQuery) GetPreloads() []string {
+	return q.Preloads
+}
+
+// GetJoins returns the associations to load with a join in the page query.
+func (q *ListQuery) GetJoins() []string {
+	return q.Joins
+}
+
 // GetOffset calculates and returns the offset for pagination based on the current page and size.
 func (q *ListQuery) GetOffset() int {
 	if q.Page == 0 {
@@ -236,6 +265,7 @@ func ListResultToDTO[TModel any, TDTO any](listResult *ListResult[TModel]) (*Lis
 		Size:            listResult.Size,
 		Page:            listResult.Page,
 		TotalCount:      listResult.TotalCount,
+		FilteredCount:   listResult.FilteredCount,
 		TotalPages:      listResult.TotalPages,
 		HasPreviousPage: listResult.HasPreviousPage,
 		HasNextPage:     listResult.HasNextPage,
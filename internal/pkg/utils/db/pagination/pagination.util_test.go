@@ -144,6 +144,31 @@ func TestListResultToDTOSuccessfulMapping(t *testing.T) {
 	}
 }
 
+func TestNewFilteredListResultPaginatesByFilteredCount(t *testing.T) {
+	result := NewFilteredListResult(10, 1, 100, 15, []int{1, 2, 3})
+
+	if result.TotalCount != 100 {
+		t.Errorf("expected total count 100, got %d", result.TotalCount)
+	}
+	if result.FilteredCount != 15 {
+		t.Errorf("expected filtered count 15, got %d", result.FilteredCount)
+	}
+	if result.TotalPages != 2 {
+		t.Errorf("expected 2 pages for 15 filtered records, got %d", result.TotalPages)
+	}
+	if !result.HasNextPage {
+		t.Error("expected a next page")
+	}
+}
+
+func TestNewListResultUsesTotalCountAsFilteredCount(t *testing.T) {
+	result := NewListResult(10, 1, 25, []int{1})
+
+	if result.FilteredCount != 25 || result.TotalPages != 3 {
+		t.Errorf("expected filtered count 25 over 3 pages, got %d over %d", result.FilteredCount, result.TotalPages)
+	}
+}
+
 // Initializes ListQuery with given size and page
 func TestNewListQueryInitialization(t *testing.T) {
 	size := 10